@@ -47,13 +47,15 @@ var (
 // Config is the user given configuration to do additional setup
 // during serve.
 type Config struct {
-	Accounts  []Account              `yaml:"accounts"`
-	Validator Validator              `yaml:"validator"`
-	Faucet    Faucet                 `yaml:"faucet"`
-	Build     Build                  `yaml:"build"`
-	Init      Init                   `yaml:"init"`
-	Genesis   map[string]interface{} `yaml:"genesis"`
-	Servers   Servers                `yaml:"servers"`
+	Accounts   []Account              `yaml:"accounts"`
+	Validator  Validator              `yaml:"validator"`
+	Validators []Validator            `yaml:"validators"`
+	Faucet     Faucet                 `yaml:"faucet"`
+	Build      Build                  `yaml:"build"`
+	Init       Init                   `yaml:"init"`
+	Genesis    map[string]interface{} `yaml:"genesis"`
+	Servers    Servers                `yaml:"servers"`
+	Wasm       Wasm                   `yaml:"wasm"`
 }
 
 // AccountByName finds account by name.
@@ -86,7 +88,27 @@ type Validator struct {
 // Build holds build configs.
 type Build struct {
 	Binary string `yaml:"binary"`
-	Proto  Proto  `yaml:"proto"`
+
+	// Main is the relative path of the main package of the app's binary.
+	Main string `yaml:"main"`
+
+	// Output is the directory where the binaries are installed, GOBIN by default.
+	Output string `yaml:"output"`
+
+	// LDFlags holds the linker flags passed to go build after the ones set by Starport.
+	LDFlags []string `yaml:"ldflags"`
+
+	// Tags holds the build tags, e.g.: ledger, netgo.
+	Tags []string `yaml:"tags"`
+
+	// CGO enables or disables cgo, the environment's setting is used when it's not set.
+	CGO *bool `yaml:"cgo"`
+
+	// SkipTidy skips go mod tidy and go mod verify before building, e.g.: when the dependencies
+	// can't be downloaded. they're always skipped when the app is vendored.
+	SkipTidy bool `yaml:"skip_tidy"`
+
+	Proto Proto `yaml:"proto"`
 }
 
 // Proto holds proto build configs.
@@ -97,6 +119,9 @@ type Proto struct {
 	// ThirdPartyPath is the relative path of where the third party proto files are
 	// located that used by the app.
 	ThirdPartyPaths []string `yaml:"third_party_paths"`
+
+	// Backend is the toolchain generating the code of the proto files, protoc or buf.
+	Backend string `yaml:"backend"`
 }
 
 // Faucet configuration.
@@ -133,6 +158,30 @@ type Init struct {
 	KeyringBackend string `yaml:"keyring-backend"`
 }
 
+// Wasm holds the CosmWasm configs.
+type Wasm struct {
+	// Contracts holds the contracts stored and instantiated after the chain starts.
+	Contracts []Contract `yaml:"contracts"`
+}
+
+// Contract is a CosmWasm contract deployed during serve.
+type Contract struct {
+	// Path is the relative path of the .wasm file of the contract.
+	Path string `yaml:"path"`
+
+	// Msg is the instantiate message of the contract.
+	Msg map[string]interface{} `yaml:"msg"`
+
+	// Label is the label of the contract instance.
+	Label string `yaml:"label"`
+
+	// Admin is the name of the account allowed to migrate the contract, optional.
+	Admin string `yaml:"admin"`
+
+	// From is the name of the account deploying the contract, the validator by default.
+	From string `yaml:"from"`
+}
+
 // Servers keeps configuration related to started servers.
 type Servers struct {
 	RPCAddr      string `yaml:"rpc-address"`
@@ -153,6 +202,10 @@ func Parse(r io.Reader) (Config, error) {
 	if err := mergo.Merge(&conf, DefaultConf); err != nil {
 		return Config{}, err
 	}
+	// the first validator of the list is the validator of the served node.
+	if len(conf.Validators) > 0 && conf.Validator.Name == "" {
+		conf.Validator = conf.Validators[0]
+	}
 	return conf, validate(conf)
 }
 
@@ -174,6 +227,37 @@ func validate(conf Config) error {
 	if conf.Validator.Name == "" {
 		return &ValidationError{"validator is required"}
 	}
+	if len(conf.Validators) > 0 && conf.Validator != conf.Validators[0] {
+		return &ValidationError{"validator and validators can't be both set, list all the validators in validators"}
+	}
+	names := make(map[string]bool)
+	for _, validator := range conf.Validators {
+		if validator.Name == "" || validator.Staked == "" {
+			return &ValidationError{"name and staked of validators are required"}
+		}
+		if names[validator.Name] {
+			return &ValidationError{fmt.Sprintf("validator %s is listed more than once", validator.Name)}
+		}
+		names[validator.Name] = true
+	}
+	switch conf.Build.Proto.Backend {
+	case "", "protoc", "buf":
+	default:
+		return &ValidationError{fmt.Sprintf("proto backend %s is not supported, use protoc or buf", conf.Build.Proto.Backend)}
+	}
+	for _, contract := range conf.Wasm.Contracts {
+		if contract.Path == "" {
+			return &ValidationError{"path of wasm contracts is required"}
+		}
+		if contract.Label == "" {
+			return &ValidationError{fmt.Sprintf("label of wasm contract %s is required", contract.Path)}
+		}
+		for _, name := range []string{contract.Admin, contract.From} {
+			if _, ok := conf.AccountByName(name); name != "" && !ok {
+				return &ValidationError{fmt.Sprintf("account %s of wasm contract %s is not found", name, contract.Path)}
+			}
+		}
+	}
 	return nil
 }
 
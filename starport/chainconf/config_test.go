@@ -1,6 +1,8 @@
 package conf
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -49,3 +51,140 @@ accounts:
 	_, err := Parse(strings.NewReader(confyml))
 	require.Equal(t, &ValidationError{"validator is required"}, err)
 }
+
+func TestParseWasmContracts(t *testing.T) {
+	confyml := `
+accounts:
+  - name: me
+    coins: ["1000token", "100000000stake"]
+validator:
+  name: me
+  staked: "100000000stake"
+wasm:
+  contracts:
+    - path: contracts/cw20_base.wasm
+      label: token
+      admin: me
+      msg:
+        name: Token
+        initial_balances:
+          - address: cosmos1
+            amount: "100"
+`
+
+	conf, err := Parse(strings.NewReader(confyml))
+	require.NoError(t, err)
+	require.Len(t, conf.Wasm.Contracts, 1)
+	contract := conf.Wasm.Contracts[0]
+	require.Equal(t, "contracts/cw20_base.wasm", contract.Path)
+	require.Equal(t, "token", contract.Label)
+	require.Equal(t, "me", contract.Admin)
+
+	msg, err := json.Marshal(contract.Msg)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"Token","initial_balances":[{"address":"cosmos1","amount":"100"}]}`, string(msg))
+}
+
+func TestParseWasmContractsUnknownAccount(t *testing.T) {
+	confyml := `
+accounts:
+  - name: me
+    coins: ["1000token", "100000000stake"]
+validator:
+  name: me
+  staked: "100000000stake"
+wasm:
+  contracts:
+    - path: contracts/cw20_base.wasm
+      label: token
+      admin: you
+`
+
+	_, err := Parse(strings.NewReader(confyml))
+	require.Equal(t, &ValidationError{"account you of wasm contract contracts/cw20_base.wasm is not found"}, err)
+}
+
+func TestParseBuild(t *testing.T) {
+	confyml := `
+accounts:
+  - name: me
+    coins: ["1000token", "100000000stake"]
+validator:
+  name: me
+  staked: "100000000stake"
+build:
+  main: cmd/custom
+  output: bin
+  ldflags: ["-s", "-w"]
+  tags: ["ledger", "netgo"]
+  cgo: false
+  skip_tidy: true
+`
+
+	conf, err := Parse(strings.NewReader(confyml))
+	require.NoError(t, err)
+	require.Equal(t, "cmd/custom", conf.Build.Main)
+	require.Equal(t, "bin", conf.Build.Output)
+	require.Equal(t, []string{"-s", "-w"}, conf.Build.LDFlags)
+	require.Equal(t, []string{"ledger", "netgo"}, conf.Build.Tags)
+	require.NotNil(t, conf.Build.CGO)
+	require.False(t, *conf.Build.CGO)
+	require.True(t, conf.Build.SkipTidy)
+	require.Equal(t, DefaultConf.Build.Proto, conf.Build.Proto)
+}
+
+func TestParseProtoBackend(t *testing.T) {
+	confyml := `
+accounts:
+  - name: me
+    coins: ["1000token", "100000000stake"]
+validator:
+  name: me
+  staked: "100000000stake"
+build:
+  proto:
+    backend: %s
+`
+
+	conf, err := Parse(strings.NewReader(fmt.Sprintf(confyml, "buf")))
+	require.NoError(t, err)
+	require.Equal(t, "buf", conf.Build.Proto.Backend)
+	require.Equal(t, "proto", conf.Build.Proto.Path)
+
+	_, err = Parse(strings.NewReader(fmt.Sprintf(confyml, "unknown")))
+	require.Equal(t, &ValidationError{"proto backend unknown is not supported, use protoc or buf"}, err)
+}
+
+func TestParseValidators(t *testing.T) {
+	confyml := `
+accounts:
+  - name: me
+    coins: ["1000token", "100000000stake"]
+validators:
+  - name: me
+    staked: "100000000stake"
+  - name: other
+    staked: "50000000stake"
+`
+
+	conf, err := Parse(strings.NewReader(confyml))
+	require.NoError(t, err)
+	require.Equal(t, Validator{Name: "me", Staked: "100000000stake"}, conf.Validator)
+	require.Equal(t, []Validator{
+		{Name: "me", Staked: "100000000stake"},
+		{Name: "other", Staked: "50000000stake"},
+	}, conf.Validators)
+
+	_, err = Parse(strings.NewReader(confyml + `
+validator:
+  name: me
+  staked: "1stake"
+`))
+	require.Error(t, err)
+
+	_, err = Parse(strings.NewReader(confyml + `
+  - name: other
+    staked: "1stake"
+`))
+	require.Equal(t, &ValidationError{"validator other is listed more than once"}, err)
+}
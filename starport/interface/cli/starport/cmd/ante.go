@@ -0,0 +1,32 @@
+package starportcmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tendermint/starport/starport/services/scaffolder"
+)
+
+// NewAnte creates a new command to scaffold a custom decorator of the ante handler.
+func NewAnte() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "ante [decoratorName]",
+		Short: "Generates a custom decorator of the ante handler",
+		Long: `Generates a decorator in app/ante and adds it to the ante handler of the app.
+The first decorator creates the ante handler in app/ante from the default decorators
+of the Cosmos SDK and sets it in app/app.go.`,
+		Args: cobra.ExactArgs(1),
+		RunE: anteHandler,
+	}
+	c.Flags().StringVarP(&appPath, "path", "p", "", "path of the app")
+	return c
+}
+
+func anteHandler(cmd *cobra.Command, args []string) error {
+	sc := scaffolder.New(appPath)
+	if _, err := sc.AddAnteDecorator(args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("\n🎉 Created the ante decorator `%[1]v`.\n\n", args[0])
+	return nil
+}
@@ -21,6 +21,8 @@ func NewApp() *cobra.Command {
 	}
 	c.Flags().String("address-prefix", "cosmos", "Address prefix")
 	addSdkVersionFlag(c)
+	c.AddCommand(NewAppUpgrade())
+	c.AddCommand(NewAppMigrateStargate())
 	return c
 }
 
@@ -0,0 +1,55 @@
+package starportcmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tendermint/starport/starport/services/scaffolder"
+)
+
+const flagGenesis = "genesis"
+
+// NewAppMigrateStargate creates a new command to migrate a Launchpad app to Stargate.
+func NewAppMigrateStargate() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "migrate-stargate",
+		Short: "Migrates a Launchpad app to Stargate",
+		Long: `Scaffolds the modules and types of a Launchpad app again with the Stargate templates.
+The proto definitions of the types are generated from their Go structs, app.go and the
+appd binary are replaced by their Stargate version. The sources of the Launchpad app are
+moved to .starport/launchpad, the custom logic they hold needs to be ported manually.`,
+		Args: cobra.NoArgs,
+		RunE: appMigrateStargateHandler,
+	}
+	c.Flags().String(flagGenesis, "", "genesis exported by the Launchpad app to convert to Stargate")
+	c.Flags().StringVarP(&appPath, "path", "p", "", "path of the app")
+	return c
+}
+
+func appMigrateStargateHandler(cmd *cobra.Command, args []string) error {
+	genesis, _ := cmd.Flags().GetString(flagGenesis)
+
+	sc := scaffolder.New(appPath)
+	result, err := sc.MigrateStargate(genesis)
+	if err != nil {
+		return err
+	}
+
+	for _, desc := range result.Created {
+		fmt.Printf("%s %s\n", infoColor("+"), desc)
+	}
+	if result.Genesis != "" {
+		fmt.Printf("%s converted the genesis to %s\n", infoColor("+"), result.Genesis)
+	}
+	fmt.Printf("\n🎉 Migrated the app to Stargate, the Launchpad sources are in %s.\n\n", result.Backup)
+
+	if len(result.Custom) > 0 {
+		fmt.Println("👉 Port the custom logic of the following files:")
+		fmt.Println()
+		for _, path := range result.Custom {
+			fmt.Printf(" - %s\n", path)
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package starportcmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tendermint/starport/starport/services/scaffolder"
+)
+
+const flagTo = "to"
+
+// NewAppUpgrade creates a new command to upgrade an app to a newer version of the Cosmos SDK.
+func NewAppUpgrade() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrades the app to a newer version of the Cosmos SDK",
+		Long: `Bumps the version of the Cosmos SDK in go.mod and rewrites the source of the app
+for the known breaking changes of the SDK. The third party proto files copied from the SDK
+are updated and the proto code is generated again. The changes that can't be automated are
+reported at the end.`,
+		Args: cobra.NoArgs,
+		RunE: appUpgradeHandler,
+	}
+	c.Flags().String(flagTo, "", "version of the Cosmos SDK to upgrade to, e.g.: v0.43.0")
+	c.Flags().StringVarP(&appPath, "path", "p", "", "path of the app")
+	c.MarkFlagRequired(flagTo)
+	return c
+}
+
+func appUpgradeHandler(cmd *cobra.Command, args []string) error {
+	to, _ := cmd.Flags().GetString(flagTo)
+
+	sc := scaffolder.New(appPath)
+	result, err := sc.Upgrade(to)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range result.Rewritten {
+		fmt.Printf("%s rewrote %s\n", infoColor("+"), path)
+	}
+	for _, path := range result.ProtoUpdated {
+		fmt.Printf("%s updated %s\n", infoColor("+"), path)
+	}
+	fmt.Printf("\n🎉 Upgraded the Cosmos SDK from %s to %s.\n\n", result.From, result.To)
+
+	if len(result.ManualSteps) > 0 {
+		fmt.Println("👉 Complete the upgrade with the following steps:")
+		fmt.Println()
+		for _, step := range result.ManualSteps {
+			fmt.Printf(" - %s\n", step)
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -1,11 +1,20 @@
 package starportcmd
 
 import (
+	"errors"
+
 	"github.com/spf13/cobra"
 	"github.com/tendermint/starport/starport/pkg/chaincmd"
 	"github.com/tendermint/starport/starport/services/chain"
 )
 
+const (
+	flagRelease = "release"
+	flagTargets = "targets"
+	flagImage   = "image"
+	flagOutput  = "output"
+)
+
 // NewBuild returns a new build command to build a blockchain app.
 func NewBuild() *cobra.Command {
 	c := &cobra.Command{
@@ -17,10 +26,29 @@ func NewBuild() *cobra.Command {
 	c.Flags().AddFlagSet(flagSetHomes())
 	c.Flags().StringVarP(&appPath, "path", "p", "", "path of the app")
 	c.Flags().BoolP("verbose", "v", false, "Verbose output")
+	c.Flags().Bool(flagRelease, false, "build tarballs of the binaries for a release")
+	c.Flags().StringSlice(flagTargets, nil, "release targets in the GOOS:GOARCH format, e.g.: linux:amd64,darwin:amd64")
+	c.Flags().String(flagImage, "", "build an OCI image tarball of the app tagged with a name:tag, e.g.: mars:v0.1.0")
+	c.Flags().String(flagOutput, "image.tar", "path of the OCI image tarball")
 	return c
 }
 
 func buildHandler(cmd *cobra.Command, args []string) error {
+	release, _ := cmd.Flags().GetBool(flagRelease)
+	targets, _ := cmd.Flags().GetStringSlice(flagTargets)
+	image, _ := cmd.Flags().GetString(flagImage)
+	output, _ := cmd.Flags().GetString(flagOutput)
+	switch {
+	case release && image != "":
+		return errors.New("--release and --image can't be used together")
+	case len(targets) > 0 && !release && image == "":
+		return errors.New("--targets can only be used with --release or --image")
+	case len(targets) > 1 && image != "":
+		return errors.New("an image can only be built for a single target")
+	case cmd.Flags().Changed(flagOutput) && image == "":
+		return errors.New("--output can only be used with --image")
+	}
+
 	chainOption := []chain.Option{
 		chain.LogLevel(logLevel(cmd)),
 		chain.KeyringBackend(chaincmd.KeyringBackendTest),
@@ -30,5 +58,17 @@ func buildHandler(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	return c.Build(cmd.Context())
+	switch {
+	case release:
+		_, err = c.BuildRelease(cmd.Context(), targets...)
+		return err
+	case image != "":
+		var target string
+		if len(targets) > 0 {
+			target = targets[0]
+		}
+		return c.BuildImage(cmd.Context(), image, output, target)
+	default:
+		return c.Build(cmd.Context())
+	}
 }
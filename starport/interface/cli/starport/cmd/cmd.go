@@ -38,6 +38,10 @@ func New() *cobra.Command {
 	c.AddCommand(NewFaucet())
 	c.AddCommand(NewBuild())
 	c.AddCommand(NewModule())
+	c.AddCommand(NewProto())
+	c.AddCommand(NewAnte())
+	c.AddCommand(NewScaffold())
+	c.AddCommand(NewRegenerate())
 	c.AddCommand(NewRelayer())
 	c.AddCommand(NewVersion())
 	c.AddCommand(NewNetwork())
@@ -1,12 +1,18 @@
 package starportcmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
 	"github.com/tendermint/starport/starport/services/scaffolder"
 )
 
+const (
+	flagModuleAccount = "module-account"
+	flagPermissions   = "permissions"
+)
+
 // NewModuleCreate creates a new module create command to scaffold an
 // sdk module.
 func NewModuleCreate() *cobra.Command {
@@ -17,13 +23,25 @@ func NewModuleCreate() *cobra.Command {
 		Args:  cobra.MinimumNArgs(1),
 		RunE:  createModuleHandler,
 	}
+	c.Flags().Bool(flagModuleAccount, false, "create a module account for the module to hold tokens")
+	c.Flags().StringSlice(flagPermissions, nil, "permissions of the module account: minter, burner, staking")
 	return c
 }
 
 func createModuleHandler(cmd *cobra.Command, args []string) error {
 	name := args[0]
+	moduleAccount, _ := cmd.Flags().GetBool(flagModuleAccount)
+	permissions, _ := cmd.Flags().GetStringSlice(flagPermissions)
+
+	var options []scaffolder.ModuleCreationOption
+	if moduleAccount {
+		options = append(options, scaffolder.WithModuleAccount(permissions...))
+	} else if len(permissions) > 0 {
+		return errors.New("permissions can only be given to a module account, use --module-account")
+	}
+
 	sc := scaffolder.New(appPath)
-	if err := sc.CreateModule(name); err != nil {
+	if _, err := sc.CreateModule(name, options...); err != nil {
 		return err
 	}
 	fmt.Printf("\n🎉 Module created %s.\n\n", name)
@@ -0,0 +1,14 @@
+package starportcmd
+
+import "github.com/spf13/cobra"
+
+// NewProto creates a new command that holds some other sub commands
+// related to the proto files of an app.
+func NewProto() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "proto",
+		Short: "Manage the proto files of your app",
+	}
+	c.AddCommand(NewProtoCheck())
+	return c
+}
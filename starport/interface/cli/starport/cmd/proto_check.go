@@ -0,0 +1,65 @@
+package starportcmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/tendermint/starport/starport/pkg/protocheck"
+	"github.com/tendermint/starport/starport/services/chain"
+)
+
+// NewProtoCheck creates a new command to lint the proto files of an app and detect breaking changes.
+func NewProtoCheck() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "check",
+		Short: "Lints the proto files and detects wire-breaking changes since the last release",
+		Long: `Lints the proto files of the app: packages are lower case, files set a go_package,
+messages are PascalCase and fields are lower_snake_case. The proto files are compared
+to the ones of the last git tag of the app to detect wire-breaking changes: removed
+messages, fields and enum values, renumbered fields and fields that changed type.
+The command fails when wire-breaking changes are found, lint issues are warnings.`,
+		Args: cobra.NoArgs,
+		RunE: protoCheckHandler,
+	}
+	c.Flags().StringVarP(&appPath, "path", "p", "", "path of the app")
+	return c
+}
+
+func protoCheckHandler(cmd *cobra.Command, args []string) error {
+	path, err := filepath.Abs(appPath)
+	if err != nil {
+		return err
+	}
+	c, err := chain.New(cmd.Context(), path)
+	if err != nil {
+		return err
+	}
+	check, err := c.CheckProto()
+	if err != nil {
+		return err
+	}
+
+	printIssues := func(title string, issues []protocheck.Issue) {
+		if len(issues) == 0 {
+			return
+		}
+		fmt.Printf("%s\n\n", title)
+		for _, issue := range issues {
+			fmt.Printf(" - %s\n", issue)
+		}
+		fmt.Println()
+	}
+	printIssues("🔍 Lint issues:", check.Lint)
+
+	switch {
+	case check.Tag == "":
+		fmt.Println("No release tag found, wire-breaking changes are not checked.")
+	case len(check.Breaking) > 0:
+		printIssues(fmt.Sprintf("💥 Wire-breaking changes since %s:", check.Tag), check.Breaking)
+		return fmt.Errorf("found %d wire-breaking changes since %s", len(check.Breaking), check.Tag)
+	default:
+		fmt.Printf("✅ No wire-breaking changes since %s.\n", check.Tag)
+	}
+	return nil
+}
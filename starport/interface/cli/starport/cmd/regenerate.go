@@ -0,0 +1,51 @@
+package starportcmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tendermint/starport/starport/services/scaffolder"
+)
+
+// NewRegenerate creates a new command to apply the templates of the current
+// Starport version to the scaffolded types of an app.
+func NewRegenerate() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "regenerate",
+		Short: "Applies the templates of this Starport version to the scaffolded types",
+		Long: `Renders the templates of the scaffolded types again and applies the changes to the app.
+Files that were not edited are replaced. Changes are merged into edited files, and files
+with conflicting changes are reported and left untouched.`,
+		Args: cobra.NoArgs,
+		RunE: regenerateHandler,
+	}
+	c.Flags().StringVarP(&appPath, "path", "p", "", "path of the app")
+	return c
+}
+
+func regenerateHandler(cmd *cobra.Command, args []string) error {
+	sc := scaffolder.New(appPath)
+	result, err := sc.Regenerate()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range result.Updated {
+		fmt.Printf("%s updated %s\n", infoColor("+"), path)
+	}
+	for _, path := range result.Merged {
+		fmt.Printf("%s merged the changes into %s\n", infoColor("+"), path)
+	}
+	for _, path := range result.Conflicts {
+		fmt.Printf("%s %s conflicts with the changes, left untouched\n", infoColor("!"), path)
+	}
+	switch {
+	case len(result.Conflicts) > 0:
+		fmt.Printf("\n%d file(s) need to be updated manually.\n\n", len(result.Conflicts))
+	case len(result.Updated) == 0 && len(result.Merged) == 0:
+		fmt.Printf("\n🎉 The scaffolded types are up to date.\n\n")
+	default:
+		fmt.Printf("\n🎉 Regenerated the scaffolded types.\n\n")
+	}
+	return nil
+}
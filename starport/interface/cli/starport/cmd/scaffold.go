@@ -0,0 +1,14 @@
+package starportcmd
+
+import "github.com/spf13/cobra"
+
+// NewScaffold creates a new command that holds some other sub commands
+// related to scaffolding apps from declarative specs.
+func NewScaffold() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "scaffold",
+		Short: "Scaffold modules and types from a spec file",
+	}
+	c.AddCommand(NewScaffoldApply())
+	return c
+}
@@ -0,0 +1,49 @@
+package starportcmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tendermint/starport/starport/services/scaffolder"
+)
+
+// NewScaffoldApply creates a new command to scaffold everything described
+// in a spec file that doesn't exist in the app yet.
+func NewScaffoldApply() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "apply [spec.yml]",
+		Short: "Scaffolds the dependencies, modules and types listed in a spec file",
+		Long: `Scaffolds the dependencies, modules and types listed in a spec file.
+Items that already exist in the app are skipped, so the same spec can be applied many times.`,
+		Args: cobra.ExactArgs(1),
+		RunE: scaffoldApplyHandler,
+	}
+	c.Flags().StringVarP(&appPath, "path", "p", "", "path of the app")
+	return c
+}
+
+func scaffoldApplyHandler(cmd *cobra.Command, args []string) error {
+	spec, err := scaffolder.ParseSpecFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	sc := scaffolder.New(appPath)
+	result, err := sc.Apply(spec)
+	if err != nil {
+		return err
+	}
+
+	for _, desc := range result.Skipped {
+		fmt.Printf("%s %s already exists, skipped\n", infoColor("-"), desc)
+	}
+	for _, desc := range result.Created {
+		fmt.Printf("%s created %s\n", infoColor("+"), desc)
+	}
+	if len(result.Created) == 0 {
+		fmt.Printf("\n🎉 Nothing to scaffold, the app is up to date with the spec.\n\n")
+		return nil
+	}
+	fmt.Printf("\n🎉 Applied the spec %s.\n\n", args[0])
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 const flagForceReset = "force-reset"
 const flagResetOnce = "reset-once"
+const flagValidators = "validators"
 
 var appPath string
 
@@ -24,6 +25,7 @@ func NewServe() *cobra.Command {
 	c.Flags().BoolP("verbose", "v", false, "Verbose output")
 	c.Flags().BoolP(flagForceReset, "f", false, "Force reset of the app state on start and every source change")
 	c.Flags().BoolP(flagResetOnce, "r", false, "Reset of the app state on first start")
+	c.Flags().Int(flagValidators, 0, "Number of validators of the local testnet, the validators of config.yml are used by default")
 
 	return c
 }
@@ -56,5 +58,11 @@ func serveHandler(cmd *cobra.Command, args []string) error {
 		serveOptions = append(serveOptions, chain.ServeResetOnce())
 	}
 
+	validators, err := cmd.Flags().GetInt(flagValidators)
+	if err != nil {
+		return err
+	}
+	serveOptions = append(serveOptions, chain.ServeValidators(validators))
+
 	return c.Serve(cmd.Context(), serveOptions...)
 }
@@ -24,6 +24,8 @@ func NewType() *cobra.Command {
 
 	c.Flags().String(moduleFlag, "", "Module to add the type into. Default: app's main module")
 
+	c.AddCommand(NewTypeAddField())
+
 	return c
 }
 
@@ -32,7 +34,7 @@ func typeHandler(cmd *cobra.Command, args []string) error {
 	module, _ := cmd.Flags().GetString(moduleFlag)
 
 	sc := scaffolder.New(appPath)
-	if err := sc.AddType(module, args[0], args[1:]...); err != nil {
+	if _, err := sc.AddType(module, args[0], args[1:]...); err != nil {
 		return err
 	}
 	fmt.Printf("\n🎉 Created a type `%[1]v`.\n\n", args[0])
@@ -0,0 +1,32 @@
+package starportcmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tendermint/starport/starport/services/scaffolder"
+)
+
+// NewTypeAddField creates a new command to add fields to an existing type.
+func NewTypeAddField() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "add-field [typeName] [field1] [field2] ...",
+		Short: "Adds fields to an existing type",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  typeAddFieldHandler,
+	}
+	c.Flags().StringVarP(&appPath, "path", "p", "", "path of the app")
+	c.Flags().String(moduleFlag, "", "Module of the type. Default: app's main module")
+	return c
+}
+
+func typeAddFieldHandler(cmd *cobra.Command, args []string) error {
+	module, _ := cmd.Flags().GetString(moduleFlag)
+
+	sc := scaffolder.New(appPath)
+	if _, err := sc.AddField(module, args[0], args[1:]...); err != nil {
+		return err
+	}
+	fmt.Printf("\n🎉 Added fields to the type `%[1]v`.\n\n", args[0])
+	return nil
+}
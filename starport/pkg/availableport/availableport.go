@@ -19,6 +19,11 @@ func Find(n int) (ports []int, err error) {
 			rand.Seed(time.Now().UnixNano())
 			port := rand.Intn(max-min+1) + min
 
+			// the ports found are distinct.
+			if contains(ports, port) {
+				continue
+			}
+
 			conn, err := net.Dial("tcp", fmt.Sprintf(":%d", port))
 			// if there is an error, this might mean that no one is listening from this port
 			// which is what we need.
@@ -32,3 +37,12 @@ func Find(n int) (ports []int, err error) {
 	}
 	return ports, nil
 }
+
+func contains(ports []int, port int) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
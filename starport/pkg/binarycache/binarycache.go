@@ -0,0 +1,194 @@
+// Package binarycache caches built binaries by a key identifying what they're built from, e.g.:
+// the checksum of their sources and build flags. the least recently used entries are removed
+// once the cache grows over its size limit.
+package binarycache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultMaxSize is the default size limit of a cache in bytes.
+const DefaultMaxSize = 1 << 30
+
+// Cache is a cache of binaries in a dir, the binaries of an entry are stored in <dir>/<key>.
+type Cache struct {
+	path    string
+	maxSize int64
+}
+
+// Option configures a cache.
+type Option func(*Cache)
+
+// MaxSize sets the size limit of the cache in bytes.
+func MaxSize(size int64) Option {
+	return func(c *Cache) {
+		c.maxSize = size
+	}
+}
+
+// New returns the cache of binaries at path.
+func New(path string, options ...Option) Cache {
+	c := Cache{
+		path:    path,
+		maxSize: DefaultMaxSize,
+	}
+	for _, apply := range options {
+		apply(&c)
+	}
+	return c
+}
+
+// Key returns a cache key from parts, e.g.: checksums and build flags.
+func Key(parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		// the length of the parts is hashed so parts can't be shifted into each other.
+		h.Write([]byte{byte(len(part) >> 24), byte(len(part) >> 16), byte(len(part) >> 8), byte(len(part))})
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Put caches the binaries at paths under key, replacing the entry with the same key if any.
+func (c Cache) Put(key string, paths ...string) error {
+	if err := os.MkdirAll(c.path, 0755); err != nil {
+		return err
+	}
+
+	// the entry is moved to its path once complete so the cache never holds a partial entry.
+	tmpPath, err := ioutil.TempDir(c.path, ".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpPath)
+
+	for _, path := range paths {
+		if err := copyFile(path, filepath.Join(tmpPath, filepath.Base(path))); err != nil {
+			return err
+		}
+	}
+
+	entryPath := filepath.Join(c.path, key)
+	if err := os.RemoveAll(entryPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, entryPath); err != nil {
+		return err
+	}
+	if err := touch(entryPath); err != nil {
+		return err
+	}
+	return c.evict()
+}
+
+// Restore copies the binaries cached under key to dir, it returns false when key isn't cached.
+func (c Cache) Restore(key, dir string) (restored bool, err error) {
+	entryPath := filepath.Join(c.path, key)
+	files, err := ioutil.ReadDir(entryPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, err
+	}
+	for _, file := range files {
+		// binaries are replaced with a rename, so a running binary isn't overwritten.
+		dst := filepath.Join(dir, file.Name())
+		if err := copyFile(filepath.Join(entryPath, file.Name()), dst+".tmp"); err != nil {
+			return false, err
+		}
+		if err := os.Rename(dst+".tmp", dst); err != nil {
+			return false, err
+		}
+	}
+
+	return true, touch(entryPath)
+}
+
+// entry is an entry of the cache.
+type entry struct {
+	path    string
+	size    int64
+	lastUse time.Time
+}
+
+// evict removes the least recently used entries until the cache fits in its size limit,
+// the most recently used entry is always kept.
+func (c Cache) evict() error {
+	infos, err := ioutil.ReadDir(c.path)
+	if err != nil {
+		return err
+	}
+
+	var (
+		entries []entry
+		size    int64
+	)
+	for _, info := range infos {
+		// skip the entries being put.
+		if !info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		e := entry{
+			path:    filepath.Join(c.path, info.Name()),
+			lastUse: info.ModTime(),
+		}
+		files, err := ioutil.ReadDir(e.path)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			e.size += file.Size()
+		}
+		entries = append(entries, e)
+		size += e.size
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastUse.Before(entries[j].lastUse)
+	})
+
+	for i := 0; size > c.maxSize && i < len(entries)-1; i++ {
+		if err := os.RemoveAll(entries[i].path); err != nil {
+			return err
+		}
+		size -= entries[i].size
+	}
+	return nil
+}
+
+// touch marks the entry at path as used now.
+func touch(path string) error {
+	now := time.Now()
+	return os.Chtimes(path, now, now)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
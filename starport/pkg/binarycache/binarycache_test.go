@@ -0,0 +1,83 @@
+package binarycache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeBinary(t *testing.T, path, content string) string {
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0755))
+	return path
+}
+
+func TestKey(t *testing.T) {
+	require.Equal(t, Key([]byte("a"), []byte("b")), Key([]byte("a"), []byte("b")))
+	require.NotEqual(t, Key([]byte("a"), []byte("b")), Key([]byte("ab")))
+	require.NotEqual(t, Key([]byte("a"), []byte("b")), Key([]byte("a"), []byte("c")))
+}
+
+func TestPutRestore(t *testing.T) {
+	var (
+		c      = New(t.TempDir())
+		srcDir = t.TempDir()
+		dstDir = filepath.Join(t.TempDir(), "bin")
+	)
+	appd := writeBinary(t, filepath.Join(srcDir, "appd"), "appd")
+	appcli := writeBinary(t, filepath.Join(srcDir, "appcli"), "appcli")
+
+	restored, err := c.Restore("k1", dstDir)
+	require.NoError(t, err)
+	require.False(t, restored)
+
+	require.NoError(t, c.Put("k1", appd, appcli))
+
+	restored, err = c.Restore("k1", dstDir)
+	require.NoError(t, err)
+	require.True(t, restored)
+	for name, content := range map[string]string{"appd": "appd", "appcli": "appcli"} {
+		restoredContent, err := ioutil.ReadFile(filepath.Join(dstDir, name))
+		require.NoError(t, err)
+		require.Equal(t, content, string(restoredContent))
+		info, err := os.Stat(filepath.Join(dstDir, name))
+		require.NoError(t, err)
+		require.NotZero(t, info.Mode()&0100)
+	}
+}
+
+func TestEvict(t *testing.T) {
+	var (
+		path   = t.TempDir()
+		c      = New(path, MaxSize(10))
+		srcDir = t.TempDir()
+	)
+	binary := writeBinary(t, filepath.Join(srcDir, "appd"), "12345")
+
+	require.NoError(t, c.Put("k1", binary))
+	require.NoError(t, c.Put("k2", binary))
+
+	// k1 is used after k2, so k2 is the least recently used entry.
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(path, "k1"), past, past))
+	require.NoError(t, os.Chtimes(filepath.Join(path, "k2"), past.Add(-time.Minute), past.Add(-time.Minute)))
+	restored, err := c.Restore("k1", t.TempDir())
+	require.NoError(t, err)
+	require.True(t, restored)
+
+	require.NoError(t, c.Put("k3", binary))
+	require.DirExists(t, filepath.Join(path, "k1"))
+	require.NoDirExists(t, filepath.Join(path, "k2"))
+	require.DirExists(t, filepath.Join(path, "k3"))
+
+	// the most recently used entry is kept even when it's over the limit.
+	c = New(path, MaxSize(1))
+	require.NoError(t, c.Put("k4", binary))
+	entries, err := ioutil.ReadDir(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "k4", entries[0].Name())
+}
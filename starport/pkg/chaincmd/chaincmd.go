@@ -37,6 +37,11 @@ const (
 	optionValidatorGasPrices               = "--gas-prices"
 	optionYes                              = "--yes"
 	optionHomeClient                       = "--home-client"
+	optionFrom                             = "--from"
+	optionGas                              = "--gas"
+	optionBroadcastMode                    = "--broadcast-mode"
+	optionLabel                            = "--label"
+	optionAdmin                            = "--admin"
 
 	constTendermint = "tendermint"
 	constJSON       = "json"
@@ -437,6 +442,53 @@ func (c ChainCmd) BankSendCommand(fromAddress, toAddress, amount string) step.Op
 	return c.cliCommand(command)
 }
 
+// WasmStoreCommand returns the command to store the wasm contract at path on the chain.
+func (c ChainCmd) WasmStoreCommand(fromAccount, path string) step.Option {
+	command := []string{
+		commandTx,
+		"wasm",
+		"store",
+		path,
+		optionFrom, fromAccount,
+		optionGas, "auto",
+		optionBroadcastMode, "block",
+		optionOutput, constJSON,
+		optionYes,
+	}
+
+	command = c.attachChainID(command)
+	command = c.attachKeyringBackend(command)
+
+	return c.cliCommand(command)
+}
+
+// WasmInstantiateCommand returns the command to instantiate the stored wasm code with msg.
+// admin is the address allowed to migrate the contract, it's optional.
+func (c ChainCmd) WasmInstantiateCommand(fromAccount, codeID, msg, label, admin string) step.Option {
+	command := []string{
+		commandTx,
+		"wasm",
+		"instantiate",
+		codeID,
+		msg,
+		optionLabel, label,
+		optionFrom, fromAccount,
+		optionGas, "auto",
+		optionBroadcastMode, "block",
+		optionOutput, constJSON,
+		optionYes,
+	}
+
+	if admin != "" {
+		command = append(command, optionAdmin, admin)
+	}
+
+	command = c.attachChainID(command)
+	command = c.attachKeyringBackend(command)
+
+	return c.cliCommand(command)
+}
+
 // QueryTxEventsCommand returns the command to query events.
 func (c ChainCmd) QueryTxEventsCommand(query string) step.Option {
 	command := []string{
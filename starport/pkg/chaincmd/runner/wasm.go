@@ -0,0 +1,85 @@
+package chaincmdrunner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/starport/starport/pkg/cmdrunner/step"
+)
+
+// WasmStore stores the wasm contract at path on the chain from fromAccount and
+// returns the id of the stored code.
+func (r Runner) WasmStore(ctx context.Context, fromAccount, path string) (codeID string, err error) {
+	out, err := r.runTx(ctx, r.cc.WasmStoreCommand(fromAccount, path))
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot store %s", path)
+	}
+	if codeID = out.attribute("code_id"); codeID == "" {
+		return "", fmt.Errorf("cannot find the code id of %s", path)
+	}
+	return codeID, nil
+}
+
+// WasmInstantiate instantiates the stored wasm code with the JSON encoded msg from fromAccount
+// and returns the address of the contract. admin is the address allowed to migrate the contract.
+func (r Runner) WasmInstantiate(ctx context.Context, fromAccount, codeID, msg, label, admin string) (address string, err error) {
+	out, err := r.runTx(ctx, r.cc.WasmInstantiateCommand(fromAccount, codeID, msg, label, admin))
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot instantiate code %s", codeID)
+	}
+	if address = out.attribute("_contract_address", "contract_address"); address == "" {
+		return "", fmt.Errorf("cannot find the contract address of code %s", codeID)
+	}
+	return address, nil
+}
+
+// txResult is the output of a transaction broadcasted in block mode.
+type txResult struct {
+	Code   int    `json:"code"`
+	RawLog string `json:"raw_log"`
+	Logs   []struct {
+		Events []struct {
+			Type  string `json:"type"`
+			Attrs []struct {
+				Key   string `json:"key"`
+				Value string `json:"value"`
+			} `json:"attributes"`
+		} `json:"events"`
+	} `json:"logs"`
+}
+
+// attribute returns the value of the first event attribute having one of the keys.
+func (t txResult) attribute(keys ...string) string {
+	for _, log := range t.Logs {
+		for _, e := range log.Events {
+			for _, attr := range e.Attrs {
+				for _, key := range keys {
+					if attr.Key == key {
+						return attr.Value
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// runTx runs the tx command and decodes its result.
+func (r Runner) runTx(ctx context.Context, command step.Option) (txResult, error) {
+	var out txResult
+	b := &bytes.Buffer{}
+
+	if err := r.run(ctx, runOptions{stdout: b}, command); err != nil {
+		return out, err
+	}
+	if err := json.NewDecoder(b).Decode(&out); err != nil {
+		return out, err
+	}
+	if out.Code > 0 {
+		return out, fmt.Errorf("SDK code %d: %s", out.Code, out.RawLog)
+	}
+	return out, nil
+}
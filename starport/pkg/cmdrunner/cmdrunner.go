@@ -11,11 +11,12 @@ import (
 )
 
 type Runner struct {
-	endSignal   os.Signal
-	stdout      io.Writer
-	stderr      io.Writer
-	workdir     string
-	runParallel bool
+	endSignal     os.Signal
+	stdout        io.Writer
+	stderr        io.Writer
+	workdir       string
+	runParallel   bool
+	parallelLimit int
 }
 
 type Option func(*Runner)
@@ -44,6 +45,14 @@ func RunParallel() Option {
 	}
 }
 
+// RunParallelLimit runs the steps in parallel with at most n of them running at once.
+func RunParallelLimit(n int) Option {
+	return func(r *Runner) {
+		r.runParallel = true
+		r.parallelLimit = n
+	}
+}
+
 // EndSignal configures s to be signaled to the processes to end them.
 func EndSignal(s os.Signal) Option {
 	return func(r *Runner) {
@@ -67,10 +76,29 @@ func (r *Runner) Run(ctx context.Context, steps ...*step.Step) error {
 		return nil
 	}
 	g, ctx := errgroup.WithContext(ctx)
+
+	// slots limits the number of steps running in parallel.
+	var slots chan struct{}
+	if r.runParallel && r.parallelLimit > 0 {
+		slots = make(chan struct{}, r.parallelLimit)
+	}
+	release := func() {
+		if slots != nil {
+			<-slots
+		}
+	}
+
 	for _, s := range steps {
 		// copy s to a new variable to allocate a new address
 		// so we can safely use it inside goroutines spawned in this loop.
 		s := s
+		if slots != nil {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
 		if err := ctx.Err(); err != nil {
 			return err
 		}
@@ -100,6 +128,7 @@ func (r *Runner) Run(ctx context.Context, steps ...*step.Step) error {
 		c := r.newCommand(s)
 		startErr := c.Start()
 		if startErr != nil {
+			release()
 			if err := runPostExecs(startErr); err != nil {
 				return err
 			}
@@ -119,6 +148,7 @@ func (r *Runner) Run(ctx context.Context, steps ...*step.Step) error {
 		}
 		if r.runParallel {
 			g.Go(func() error {
+				defer release()
 				return runPostExecs(c.Wait())
 			})
 		} else if err := runPostExecs(c.Wait()); err != nil {
@@ -0,0 +1,240 @@
+// Package codemod rewrites Go source files to follow the API changes of their dependencies.
+package codemod
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Rewrite changes the syntax tree of a Go file, it returns true when the file is changed.
+type Rewrite func(file *ast.File) bool
+
+// RenameImport replaces the import paths starting with oldPrefix by newPrefix,
+// e.g.: to follow a package moved to another module.
+func RenameImport(oldPrefix, newPrefix string) Rewrite {
+	return func(file *ast.File) bool {
+		var changed bool
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			if importPath != oldPrefix && !strings.HasPrefix(importPath, oldPrefix+"/") {
+				continue
+			}
+			newPath := newPrefix + strings.TrimPrefix(importPath, oldPrefix)
+
+			// keep the name the package was used with
+			base := path.Base(importPath)
+			if spec.Name == nil && base != path.Base(newPath) && token.IsIdentifier(base) {
+				spec.Name = ast.NewIdent(base)
+			}
+			spec.Path.Value = strconv.Quote(newPath)
+			changed = true
+		}
+		return changed
+	}
+}
+
+// RenameSelector renames the identifier name of the package imported from pkgPath,
+// e.g.: codec.Marshaler to codec.Codec.
+func RenameSelector(pkgPath, name, newName string) Rewrite {
+	return func(file *ast.File) bool {
+		pkgName, ok := importName(file, pkgPath)
+		if !ok {
+			return false
+		}
+		var changed bool
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if x, ok := sel.X.(*ast.Ident); ok && x.Name == pkgName && sel.Sel.Name == name {
+				sel.Sel.Name = newName
+				changed = true
+			}
+			return true
+		})
+		return changed
+	}
+}
+
+// RenameMethod renames the calls to the method name on one of the receivers, e.g.: with the receiver
+// k.cdc, k.cdc.MarshalBinaryBare(v) to k.cdc.Marshal(v). receivers are written as in the source,
+// the calls to the method on other receivers are left unchanged since they may have a different type.
+func RenameMethod(receivers []string, name, newName string) Rewrite {
+	return func(file *ast.File) bool {
+		var changed bool
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != name {
+				return true
+			}
+			receiver := exprName(sel.X)
+			for _, r := range receivers {
+				if receiver != "" && receiver == r {
+					sel.Sel.Name = newName
+					changed = true
+					break
+				}
+			}
+			return true
+		})
+		return changed
+	}
+}
+
+// exprName returns the name of the identifier or of the chain of selectors expr, e.g.: k.cdc.
+// it's empty for the other expressions.
+func exprName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		if x := exprName(e.X); x != "" {
+			return x + "." + e.Sel.Name
+		}
+	}
+	return ""
+}
+
+// AddArg adds the expression arg at index to the calls of the function name of the package
+// imported from pkgPath that have argCount arguments. a negative index appends the argument.
+func AddArg(pkgPath, name string, argCount, index int, arg string) Rewrite {
+	return func(file *ast.File) bool {
+		pkgName, ok := importName(file, pkgPath)
+		if !ok {
+			return false
+		}
+		var changed bool
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != argCount {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != name {
+				return true
+			}
+			if x, ok := sel.X.(*ast.Ident); !ok || x.Name != pkgName {
+				return true
+			}
+			expr, err := parser.ParseExpr(arg)
+			if err != nil {
+				return true
+			}
+			clearPos(expr)
+			at := index
+			if at < 0 || at > len(call.Args) {
+				at = len(call.Args)
+			}
+			call.Args = append(call.Args[:at], append([]ast.Expr{expr}, call.Args[at:]...)...)
+			changed = true
+			return true
+		})
+		return changed
+	}
+}
+
+// clearPos clears the positions of node, they are relative to the source node was parsed
+// from, so it's printed inline wherever it's inserted.
+func clearPos(node ast.Node) {
+	posType := reflect.TypeOf(token.NoPos)
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		v := reflect.ValueOf(n).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.Type() == posType {
+				f.SetInt(int64(token.NoPos))
+			}
+		}
+		return true
+	})
+}
+
+// importName returns the name used in file for the package imported from pkgPath.
+func importName(file *ast.File, pkgPath string) (name string, ok bool) {
+	for _, spec := range file.Imports {
+		if spec.Path.Value != strconv.Quote(pkgPath) {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name, true
+		}
+		return path.Base(pkgPath), true
+	}
+	return "", false
+}
+
+// ApplyFile applies the rewrites to the Go source file at path and returns true if it changed.
+func ApplyFile(path string, rewrites ...Rewrite) (bool, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return false, err
+	}
+	var changed bool
+	for _, rewrite := range rewrites {
+		if rewrite(file) {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return false, err
+	}
+	return true, ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// Apply applies the rewrites to the Go source files under dir and returns the paths of the changed files.
+// hidden dirs, vendor and the code generated from proto files are skipped.
+func Apply(dir string, rewrites ...Rewrite) (changed []string, err error) {
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := info.Name()
+		if info.IsDir() {
+			if path != dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, ".pb.go") || strings.HasSuffix(name, ".pb.gw.go") {
+			return nil
+		}
+		ok, err := ApplyFile(path, rewrites...)
+		if err != nil {
+			return err
+		}
+		if ok {
+			changed = append(changed, path)
+		}
+		return nil
+	})
+	return changed, err
+}
@@ -0,0 +1,194 @@
+package codemod
+
+import (
+	"bytes"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const src = `package app
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	upgradekeeper "github.com/cosmos/cosmos-sdk/x/upgrade/keeper"
+	"github.com/cosmos/cosmos-sdk/x/ibc/core/keeper"
+)
+
+type Keeper struct {
+	// cdc encodes the values
+	cdc codec.Marshaler
+}
+
+func (k Keeper) value(v interface{}) []byte {
+	upgradekeeper.NewKeeper(nil, nil, k.cdc, "")
+	_ = keeper.Keeper{}
+	return k.cdc.MustMarshalBinaryBare(v)
+}
+`
+
+const expected = `package app
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	upgradekeeper "github.com/cosmos/cosmos-sdk/x/upgrade/keeper"
+	"github.com/cosmos/ibc-go/modules/core/keeper"
+)
+
+type Keeper struct {
+	// cdc encodes the values
+	cdc codec.Codec
+}
+
+func (k Keeper) value(v interface{}) []byte {
+	upgradekeeper.NewKeeper(nil, nil, k.cdc, "", app.BaseApp)
+	_ = keeper.Keeper{}
+	return k.cdc.MustMarshal(v)
+}
+`
+
+func TestApply(t *testing.T) {
+	dir, err := ioutil.TempDir("", "codemod")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.go")
+	require.NoError(t, ioutil.WriteFile(path, []byte(src), 0644))
+	generated := filepath.Join(dir, "app.pb.go")
+	require.NoError(t, ioutil.WriteFile(generated, []byte(src), 0644))
+
+	changed, err := Apply(dir,
+		RenameImport("github.com/cosmos/cosmos-sdk/x/ibc/core", "github.com/cosmos/ibc-go/modules/core"),
+		RenameSelector("github.com/cosmos/cosmos-sdk/codec", "Marshaler", "Codec"),
+		RenameMethod([]string{"k.cdc"}, "MustMarshalBinaryBare", "MustMarshal"),
+		AddArg("github.com/cosmos/cosmos-sdk/x/upgrade/keeper", "NewKeeper", 4, -1, "app.BaseApp"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{path}, changed)
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(content))
+
+	content, err = ioutil.ReadFile(generated)
+	require.NoError(t, err)
+	require.Equal(t, src, string(content))
+}
+
+func TestApplyUnchanged(t *testing.T) {
+	dir, err := ioutil.TempDir("", "codemod")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.go")
+	require.NoError(t, ioutil.WriteFile(path, []byte(src), 0644))
+
+	changed, err := Apply(dir, RenameSelector("github.com/cosmos/cosmos-sdk/types", "Coin", "Coins"))
+	require.NoError(t, err)
+	require.Empty(t, changed)
+}
+
+// rewrite applies rewrite to the Go source src and returns the formatted result.
+func rewrite(t *testing.T, rewrite Rewrite, src string) (string, bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	require.NoError(t, err)
+	changed := rewrite(file)
+	var buf bytes.Buffer
+	require.NoError(t, format.Node(&buf, fset, file))
+	return buf.String(), changed
+}
+
+func TestRenameMethod(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		want    string
+		changed bool
+	}{
+		{
+			name:    "receiver",
+			src:     "package a\n\nfunc f() { k.cdc.MarshalBinaryBare(v) }\n",
+			want:    "package a\n\nfunc f() { k.cdc.Marshal(v) }\n",
+			changed: true,
+		},
+		{
+			name:    "other receivers",
+			src:     "package a\n\nfunc f() { cdc.MarshalBinaryBare(v); k.amino.MarshalBinaryBare(v); x.k.cdc.MarshalBinaryBare(v) }\n",
+			want:    "package a\n\nfunc f() { cdc.MarshalBinaryBare(v); k.amino.MarshalBinaryBare(v); x.k.cdc.MarshalBinaryBare(v) }\n",
+			changed: false,
+		},
+		{
+			name:    "receiver of a call",
+			src:     "package a\n\nfunc f() { k.codec().MarshalBinaryBare(v) }\n",
+			want:    "package a\n\nfunc f() { k.codec().MarshalBinaryBare(v) }\n",
+			changed: false,
+		},
+		{
+			name:    "other methods",
+			src:     "package a\n\nfunc f() { k.cdc.MarshalJSON(v) }\n",
+			want:    "package a\n\nfunc f() { k.cdc.MarshalJSON(v) }\n",
+			changed: false,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			content, changed := rewrite(t, RenameMethod([]string{"k.cdc"}, "MarshalBinaryBare", "Marshal"), tt.src)
+			require.Equal(t, tt.want, content)
+			require.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestAddArg(t *testing.T) {
+	const src = `package a
+
+import "example.com/m"
+
+func f() {
+	m.New(a, b)
+	m.New(a, b)
+	m.New(a)
+	m.Other(a, b)
+}
+`
+	cases := []struct {
+		name  string
+		index int
+		want  string
+	}{
+		{
+			name:  "append",
+			index: -1,
+			want:  "m.New(a, b, c)\n\tm.New(a, b, c)\n\tm.New(a)\n",
+		},
+		{
+			name:  "insert",
+			index: 0,
+			want:  "m.New(c, a, b)\n\tm.New(c, a, b)\n\tm.New(a)\n",
+		},
+		{
+			name:  "index after the arguments",
+			index: 5,
+			want:  "m.New(a, b, c)\n\tm.New(a, b, c)\n\tm.New(a)\n",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			// the rewrite is applied to several files, like Apply does.
+			add := AddArg("example.com/m", "New", 2, tt.index, "c")
+			for i := 0; i < 2; i++ {
+				content, changed := rewrite(t, add, src)
+				require.True(t, changed)
+				require.Contains(t, content, tt.want)
+				require.Contains(t, content, "m.Other(a, b)")
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package cosmosprotoc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tendermint/starport/starport/pkg/toolcache"
+)
+
+const (
+	// BackendProtoc generates code with protoc.
+	BackendProtoc = "protoc"
+
+	// BackendBuf generates code with buf.
+	BackendBuf = "buf"
+)
+
+// Backend is a proto toolchain generating the code of proto files.
+type Backend interface {
+	// Name is the name of the backend, as set in config.yml.
+	Name() string
+
+	// InstallDependencies installs the tools used by the backend to generate code.
+	InstallDependencies(ctx context.Context) error
+
+	// Prepare prepares the generation of the code of the proto files under protoPath, importing
+	// proto files from includePaths. workPath is a temporary dir the backend can write to.
+	// it returns a func returning the commands generating the code of a proto file, the commands
+	// run in the dir where the code is generated.
+	Prepare(workPath, protoPath string, includePaths []string) (commands func(path string) [][]string, err error)
+}
+
+// NewBackend returns the backend with name, protoc is used when name is empty.
+// the protoc plugins used by the backend are built into tools.
+func NewBackend(name string, tools toolcache.Cache) (Backend, error) {
+	switch name {
+	case "", BackendProtoc:
+		return NewProtoc(tools), nil
+	case BackendBuf:
+		return NewBuf(tools), nil
+	default:
+		return nil, fmt.Errorf("unknown proto backend %s, use %s or %s", name, BackendProtoc, BackendBuf)
+	}
+}
+
+// plugin is a protoc plugin generating code from proto files.
+type plugin struct {
+	// name of the plugin, protoc-gen-<name> is the binary of the plugin.
+	name string
+
+	// opt holds the options of the plugin.
+	opt string
+
+	// tool builds the binary of the plugin.
+	tool toolcache.Tool
+}
+
+// plugins generate the code of the proto files of an app.
+var plugins = []plugin{
+	{
+		name: "gocosmos",
+		opt:  "plugins=interfacetype+grpc,Mgoogle/protobuf/any.proto=github.com/cosmos/cosmos-sdk/codec/types",
+		tool: toolcache.Tool{
+			Path:    "github.com/regen-network/cosmos-proto/protoc-gen-gocosmos",
+			Version: "v0.3.1",
+			// the same gogoproto as the SDK.
+			Replace: map[string]string{
+				"github.com/gogo/protobuf": "github.com/regen-network/protobuf@v1.3.3-alpha.regen.1",
+			},
+		},
+	},
+	{
+		name: "grpc-gateway",
+		opt:  "logtostderr=true",
+		tool: toolcache.Tool{
+			Path:    "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-grpc-gateway",
+			Version: "v1.16.0",
+		},
+	},
+}
+
+// installPlugins builds the protoc plugins into tools, the plugins already built are not built again.
+func installPlugins(ctx context.Context, tools toolcache.Cache) error {
+	var pluginTools []toolcache.Tool
+	for _, plugin := range plugins {
+		pluginTools = append(pluginTools, plugin.tool)
+	}
+	return tools.Ensure(ctx, pluginTools...)
+}
@@ -0,0 +1,100 @@
+package cosmosprotoc
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/starport/starport/pkg/toolcache"
+)
+
+func TestNewBackend(t *testing.T) {
+	tools := toolcache.New("/tools")
+	for name, expected := range map[string]Backend{
+		"":       NewProtoc(tools),
+		"protoc": NewProtoc(tools),
+		"buf":    NewBuf(tools),
+	} {
+		backend, err := NewBackend(name, tools)
+		require.NoError(t, err)
+		require.Equal(t, expected, backend)
+	}
+
+	_, err := NewBackend("unknown", tools)
+	require.Error(t, err)
+}
+
+func TestProtocPrepare(t *testing.T) {
+	tools := toolcache.New("/tools")
+	commands, err := NewProtoc(tools).Prepare(t.TempDir(), "/app/proto", []string{"/app/proto", "/sdk/proto"})
+	require.NoError(t, err)
+
+	args := []string{
+		"protoc", "-I", "/app/proto", "-I", "/sdk/proto",
+		"--plugin=protoc-gen-gocosmos=" + tools.BinaryPath(plugins[0].tool),
+		"--plugin=protoc-gen-grpc-gateway=" + tools.BinaryPath(plugins[1].tool),
+	}
+	require.Equal(t, [][]string{
+		append(append([]string{}, args...), "--gocosmos_out="+plugins[0].opt+":.", "/app/proto/foo/a.proto"),
+		append(append([]string{}, args...), "--grpc-gateway_out="+plugins[1].opt+":.", "/app/proto/foo/a.proto"),
+	}, commands("/app/proto/foo/a.proto"))
+}
+
+func TestBufPrepare(t *testing.T) {
+	var (
+		workPath  = t.TempDir()
+		protoPath = t.TempDir()
+		sdkPath   = t.TempDir()
+	)
+	writeProto(t, filepath.Join(protoPath, "foo/a.proto"), "app")
+	writeProto(t, filepath.Join(protoPath, "gogoproto/gogo.proto"), "app gogo")
+	writeProto(t, filepath.Join(sdkPath, "gogoproto/gogo.proto"), "sdk gogo")
+	writeProto(t, filepath.Join(sdkPath, "cosmos/base.proto"), "sdk")
+
+	tools := toolcache.New("/tools")
+	commands, err := NewBuf(tools).Prepare(workPath, protoPath, []string{protoPath, sdkPath})
+	require.NoError(t, err)
+
+	gen, err := ioutil.ReadFile(filepath.Join(workPath, bufGenFile))
+	require.NoError(t, err)
+	require.Contains(t, string(gen), "name: gocosmos")
+	require.Contains(t, string(gen), "path: "+tools.BinaryPath(plugins[0].tool))
+	require.Contains(t, string(gen), "name: grpc-gateway")
+	require.Contains(t, string(gen), "path: "+tools.BinaryPath(plugins[1].tool))
+
+	// the proto files of the first include paths are preferred.
+	input := filepath.Join(workPath, "proto")
+	for path, content := range map[string]string{
+		"foo/a.proto":          "app",
+		"gogoproto/gogo.proto": "app gogo",
+		"cosmos/base.proto":    "sdk",
+	} {
+		combined, err := ioutil.ReadFile(filepath.Join(input, path))
+		require.NoError(t, err)
+		require.Equal(t, content, string(combined))
+	}
+
+	require.Equal(t, [][]string{{
+		"buf", "generate", input,
+		"--template", filepath.Join(workPath, bufGenFile),
+		"--path", filepath.Join(input, "foo/a.proto"),
+	}}, commands(filepath.Join(protoPath, "foo/a.proto")))
+}
+
+func TestBufPrepareModule(t *testing.T) {
+	var (
+		workPath  = t.TempDir()
+		protoPath = t.TempDir()
+	)
+	writeProto(t, filepath.Join(protoPath, bufModuleFile), "version: v1")
+
+	// the proto path of the app is used as is when it's a buf module.
+	commands, err := Buf{}.Prepare(workPath, protoPath, []string{protoPath})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{
+		"buf", "generate", protoPath,
+		"--template", filepath.Join(workPath, bufGenFile),
+		"--path", filepath.Join(protoPath, "foo/a.proto"),
+	}}, commands(filepath.Join(protoPath, "foo/a.proto")))
+}
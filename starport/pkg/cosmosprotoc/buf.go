@@ -0,0 +1,139 @@
+package cosmosprotoc
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/pkg/errors"
+	"github.com/tendermint/starport/starport/pkg/toolcache"
+	"github.com/tendermint/starport/starport/pkg/xexec"
+)
+
+// ErrBufNotInstalled is returned when buf isn't installed on the system.
+var ErrBufNotInstalled = errors.New("buf is not installed, please follow the instructions on https://docs.buf.build/installation")
+
+const (
+	// bufModuleFile is the config file of a buf module.
+	bufModuleFile = "buf.yaml"
+
+	// bufGenFile is the template of buf generate.
+	bufGenFile = "buf.gen.yaml"
+)
+
+// Buf is the backend generating code with buf.
+// the proto path of an app holding a buf.yaml is used as a buf module, with its dependencies.
+// otherwise the proto files of the app and the include paths are combined into a module,
+// the same way protoc resolves imports.
+type Buf struct {
+	tools toolcache.Cache
+}
+
+// NewBuf returns the buf backend using the plugins built into tools.
+func NewBuf(tools toolcache.Cache) Buf {
+	return Buf{tools}
+}
+
+// Name implements Backend.
+func (Buf) Name() string { return BackendBuf }
+
+// InstallDependencies installs the protoc plugins used by buf.
+func (b Buf) InstallDependencies(ctx context.Context) error {
+	if !xexec.IsCommandAvailable("buf") {
+		return ErrBufNotInstalled
+	}
+	return installPlugins(ctx, b.tools)
+}
+
+// bufGen is the buf.gen.yaml template of buf generate.
+type bufGen struct {
+	Version string         `yaml:"version"`
+	Plugins []bufGenPlugin `yaml:"plugins"`
+}
+
+type bufGenPlugin struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"`
+	Out  string `yaml:"out"`
+	Opt  string `yaml:"opt"`
+}
+
+// Prepare implements Backend.
+func (b Buf) Prepare(workPath, protoPath string, includePaths []string) (func(path string) [][]string, error) {
+	gen := bufGen{Version: "v1"}
+	for _, plugin := range plugins {
+		gen.Plugins = append(gen.Plugins, bufGenPlugin{
+			Name: plugin.name,
+			Path: b.tools.BinaryPath(plugin.tool),
+			Out:  ".",
+			Opt:  plugin.opt,
+		})
+	}
+	content, err := yaml.Marshal(gen)
+	if err != nil {
+		return nil, err
+	}
+	genPath := filepath.Join(workPath, bufGenFile)
+	if err := ioutil.WriteFile(genPath, content, 0644); err != nil {
+		return nil, err
+	}
+
+	input := protoPath
+	if _, err := os.Stat(filepath.Join(protoPath, bufModuleFile)); os.IsNotExist(err) {
+		input = filepath.Join(workPath, "proto")
+		if err := combineProto(input, includePaths); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return func(path string) [][]string {
+		rel, err := filepath.Rel(protoPath, path)
+		if err != nil {
+			rel = path
+		}
+		return [][]string{{
+			"buf",
+			"generate",
+			input,
+			"--template", genPath,
+			"--path", filepath.Join(input, rel),
+		}}
+	}, nil
+}
+
+// combineProto copies the proto files of the include paths to path. like protoc, the proto
+// files of the first include paths are preferred to the ones with the same path in the next ones.
+func combineProto(path string, includePaths []string) error {
+	for _, includePath := range includePaths {
+		err := filepath.Walk(includePath, func(file string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || !strings.HasSuffix(file, ".proto") {
+				return err
+			}
+			rel, err := filepath.Rel(includePath, file)
+			if err != nil {
+				return err
+			}
+			dst := filepath.Join(path, rel)
+			if _, err := os.Stat(dst); err == nil {
+				return nil
+			}
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return err
+			}
+			return ioutil.WriteFile(dst, content, 0644)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package cosmosprotoc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// cache records the proto files of an app generated by a previous run with their generated files.
+type cache struct {
+	// Key identifies the plugins and their options that generated the files.
+	Key string `json:"key"`
+
+	// Files maps the paths of the proto files relative to the proto path of the app to their
+	// generated files.
+	Files map[string]cacheEntry `json:"files"`
+}
+
+// cacheEntry records the code generated for a proto file.
+type cacheEntry struct {
+	// Hash is the hash of the proto file and the files it imports.
+	Hash string `json:"hash"`
+
+	// Outputs holds the paths of the generated files relative to the app path.
+	Outputs []string `json:"outputs"`
+}
+
+// cacheKey returns the key of the cache for the backend, the plugins and the go module path of an app.
+func cacheKey(gomodPath, backend string) string {
+	h := sha256.New()
+	fmt.Fprintln(h, gomodPath)
+	fmt.Fprintln(h, backend)
+	for _, plugin := range plugins {
+		fmt.Fprintln(h, plugin.name, plugin.opt, plugin.tool)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// readCache reads the cache at path, it returns an empty cache when there is no path or
+// the key doesn't match.
+func readCache(path, key string) (cache, error) {
+	c := cache{Key: key, Files: make(map[string]cacheEntry)}
+	if path == "" {
+		return c, nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	var saved cache
+	if err := json.Unmarshal(content, &saved); err != nil || saved.Key != key {
+		// an unreadable cache or one of other plugins is discarded.
+		return c, nil
+	}
+	if saved.Files != nil {
+		c.Files = saved.Files
+	}
+	return c, nil
+}
+
+func writeCache(path string, c cache) error {
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+// upToDate checks if the code generated for a proto file with hash is still in the app at projectPath.
+func (e cacheEntry) upToDate(projectPath, hash string) bool {
+	if e.Hash != hash {
+		return false
+	}
+	for _, output := range e.Outputs {
+		if _, err := os.Stat(filepath.Join(projectPath, output)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+var protoImportRe = regexp.MustCompile(`(?m)^\s*import\s+(?:public\s+|weak\s+)?"([^"]+)"\s*;`)
+
+// protoImports returns the files imported by a proto file.
+func protoImports(content []byte) []string {
+	var imports []string
+	for _, match := range protoImportRe.FindAllSubmatch(content, -1) {
+		imports = append(imports, string(match[1]))
+	}
+	return imports
+}
+
+// protoHasher hashes proto files with the files they import, resolved from the include paths.
+// a file's hash changes when one of its direct or indirect imports changes.
+type protoHasher struct {
+	includePaths []string
+	hashes       map[string]string
+}
+
+func newProtoHasher(includePaths []string) *protoHasher {
+	return &protoHasher{
+		includePaths: includePaths,
+		hashes:       make(map[string]string),
+	}
+}
+
+// hash returns the hash of the proto file at path.
+func (h *protoHasher) hash(path string) (string, error) {
+	if sum, ok := h.hashes[path]; ok {
+		return sum, nil
+	}
+	// guards against import cycles, protoc reports them.
+	h.hashes[path] = ""
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.New()
+	sum.Write(content)
+	for _, imported := range protoImports(content) {
+		fmt.Fprintf(sum, "\n%s:", imported)
+		// imports that can't be resolved are provided by protoc itself.
+		importPath, ok := h.resolve(imported)
+		if !ok {
+			continue
+		}
+		importSum, err := h.hash(importPath)
+		if err != nil {
+			return "", err
+		}
+		sum.Write([]byte(importSum))
+	}
+	h.hashes[path] = hex.EncodeToString(sum.Sum(nil))
+	return h.hashes[path], nil
+}
+
+func (h *protoHasher) resolve(imported string) (path string, ok bool) {
+	for _, includePath := range h.includePaths {
+		path := filepath.Join(includePath, imported)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// writeChanged copies the files under src to dst, the files with the same content in dst are
+// left untouched to keep their modification time. it returns the paths of the files relative to src.
+func writeChanged(src, dst string) (paths []string, err error) {
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil, nil
+	}
+	err = filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		dstPath := filepath.Join(dst, rel)
+		if current, err := ioutil.ReadFile(dstPath); err == nil && bytes.Equal(current, content) {
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(dstPath, content, 0644)
+	})
+	return paths, err
+}
+
+// isThirdParty checks if the proto file at path belongs to one of the third party proto paths.
+func isThirdParty(path string, protoThirdPartyPaths []string) bool {
+	for _, protoThirdPartyPath := range protoThirdPartyPaths {
+		if strings.HasPrefix(path, protoThirdPartyPath) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package cosmosprotoc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeProto(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+}
+
+func TestProtoImports(t *testing.T) {
+	content := []byte(`syntax = "proto3";
+import "gogoproto/gogo.proto";
+  import public "foo/bar.proto";
+import weak "foo/baz.proto" ;
+// import "commented.proto";
+`)
+	require.Equal(t, []string{"gogoproto/gogo.proto", "foo/bar.proto", "foo/baz.proto"}, protoImports(content))
+}
+
+func TestProtoHasher(t *testing.T) {
+	var (
+		protoPath      = t.TempDir()
+		thirdPartyPath = t.TempDir()
+		a              = filepath.Join(protoPath, "app/a.proto")
+		b              = filepath.Join(protoPath, "app/b.proto")
+		dep            = filepath.Join(thirdPartyPath, "dep/dep.proto")
+	)
+	writeProto(t, a, `import "app/b.proto";`)
+	writeProto(t, b, `import "dep/dep.proto"; import "google/protobuf/any.proto";`)
+	writeProto(t, dep, `message Dep {}`)
+
+	hashes := func() (string, string) {
+		h := newProtoHasher([]string{protoPath, thirdPartyPath})
+		hashA, err := h.hash(a)
+		require.NoError(t, err)
+		hashB, err := h.hash(b)
+		require.NoError(t, err)
+		return hashA, hashB
+	}
+
+	hashA, hashB := hashes()
+	sameA, sameB := hashes()
+	require.Equal(t, hashA, sameA)
+	require.Equal(t, hashB, sameB)
+
+	// a change to an indirect import changes the hashes of its dependents.
+	writeProto(t, dep, `message Dep { string field = 1; }`)
+	newA, newB := hashes()
+	require.NotEqual(t, hashA, newA)
+	require.NotEqual(t, hashB, newB)
+}
+
+func TestCache(t *testing.T) {
+	var (
+		projectPath = t.TempDir()
+		path        = filepath.Join(t.TempDir(), "cache.json")
+	)
+	writeProto(t, filepath.Join(projectPath, "x/foo/types/a.pb.go"), "package types")
+
+	c, err := readCache(path, "key")
+	require.NoError(t, err)
+	require.Empty(t, c.Files)
+
+	c.Files["a.proto"] = cacheEntry{Hash: "hash", Outputs: []string{"x/foo/types/a.pb.go"}}
+	require.NoError(t, writeCache(path, c))
+
+	c, err = readCache(path, "key")
+	require.NoError(t, err)
+	require.True(t, c.Files["a.proto"].upToDate(projectPath, "hash"))
+	require.False(t, c.Files["a.proto"].upToDate(projectPath, "other"))
+
+	// generated files removed from the app are generated again.
+	require.NoError(t, os.Remove(filepath.Join(projectPath, "x/foo/types/a.pb.go")))
+	require.False(t, c.Files["a.proto"].upToDate(projectPath, "hash"))
+
+	// the cache of other plugins is discarded.
+	c, err = readCache(path, "other")
+	require.NoError(t, err)
+	require.Empty(t, c.Files)
+}
+
+func TestWriteChanged(t *testing.T) {
+	var (
+		src = t.TempDir()
+		dst = t.TempDir()
+	)
+	writeProto(t, filepath.Join(src, "x/foo/types/a.pb.go"), "package a")
+	writeProto(t, filepath.Join(src, "x/foo/types/b.pb.go"), "package b")
+	writeProto(t, filepath.Join(dst, "x/foo/types/a.pb.go"), "package a")
+
+	unchanged := filepath.Join(dst, "x/foo/types/a.pb.go")
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(unchanged, past, past))
+
+	paths, err := writeChanged(src, dst)
+	require.NoError(t, err)
+	require.Equal(t, []string{"x/foo/types/a.pb.go", "x/foo/types/b.pb.go"}, paths)
+
+	info, err := os.Stat(unchanged)
+	require.NoError(t, err)
+	require.True(t, info.ModTime().Equal(past))
+
+	content, err := ioutil.ReadFile(filepath.Join(dst, "x/foo/types/b.pb.go"))
+	require.NoError(t, err)
+	require.Equal(t, "package b", string(content))
+}
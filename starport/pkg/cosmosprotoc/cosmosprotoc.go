@@ -3,66 +3,56 @@ package cosmosprotoc
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/mattn/go-zglob"
-	"github.com/otiai10/copy"
 	"github.com/pkg/errors"
 	"github.com/tendermint/starport/starport/pkg/cmdrunner"
 	"github.com/tendermint/starport/starport/pkg/cmdrunner/step"
 	"github.com/tendermint/starport/starport/pkg/gomodule"
-	"github.com/tendermint/starport/starport/pkg/xexec"
+	"golang.org/x/mod/module"
 )
 
-// ErrProtocNotInstalled is returned when protoc isn't installed on the system.
-var ErrProtocNotInstalled = errors.New("protoc is not installed")
-
-// InstallDependencies installs protoc dependencies needed by Cosmos ecosystem.
-func InstallDependencies(ctx context.Context, appPath string) error {
-	if !xexec.IsCommandAvailable("protoc") {
-		return ErrProtocNotInstalled
-	}
-
-	errb := &bytes.Buffer{}
-	err := cmdrunner.
-		New(
-			cmdrunner.DefaultStderr(errb),
-			cmdrunner.DefaultWorkdir(appPath),
-		).
-		Run(ctx,
-			// installs the gocosmos plugin with the version specified under the
-			// go.mod of the app.
-			step.New(
-				step.Exec(
-					"go",
-					"get",
-					"github.com/regen-network/cosmos-proto/protoc-gen-gocosmos@v0.3.1",
-				),
-			),
-			// install grpc-gateway.
-			step.New(
-				step.Exec(
-					"go",
-					"get",
-					"github.com/grpc-ecosystem/grpc-gateway/protoc-gen-grpc-gateway@v1.16.0",
-					"github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger@v1.16.0",
-					"github.com/golang/protobuf/protoc-gen-go@v1.4.3",
-				),
-			),
-		)
-	return errors.Wrap(err, errb.String())
-}
-
 var (
-	protocOuts = []string{
-		"--gocosmos_out=plugins=interfacetype+grpc,Mgoogle/protobuf/any.proto=github.com/cosmos/cosmos-sdk/codec/types:.",
-		"--grpc-gateway_out=logtostderr=true:.",
+	// protoModules are the modules hosting proto files that can be imported by the apps.
+	// IBC moved out of the SDK to its own module since v0.43.
+	protoModules = []string{
+		"github.com/cosmos/cosmos-sdk",
+		"github.com/cosmos/ibc-go",
 	}
 )
 
+// Option configures code generation.
+type Option func(*generateOptions)
+
+type generateOptions struct {
+	backend   Backend
+	cachePath string
+}
+
+// WithBackend generates the code with backend, protoc is used by default.
+func WithBackend(backend Backend) Option {
+	return func(o *generateOptions) {
+		o.backend = backend
+	}
+}
+
+// WithCache caches the hashes of the proto files and their imports at path, the code
+// of the proto files unchanged since the last generation is not generated again.
+func WithCache(path string) Option {
+	return func(o *generateOptions) {
+		o.cachePath = path
+	}
+}
+
 // Generate generates code from proto app's proto files.
 // make sure that all paths are absolute.
 func Generate(
@@ -71,7 +61,15 @@ func Generate(
 	gomodPath,
 	protoPath string,
 	protoThirdPartyPaths []string,
+	options ...Option,
 ) error {
+	o := generateOptions{
+		backend: Protoc{},
+	}
+	for _, apply := range options {
+		apply(&o)
+	}
+
 	// Cosmos SDK hosts proto files of own x/ modules and some third party ones needed by itself and
 	// blockchain apps. Generate should be aware of these and make them available to the blockchain
 	// app that wants to generate code for its own proto.
@@ -80,10 +78,17 @@ func Generate(
 	// app's dependencies are download by 'go mod' and cached under the local filesystem.
 	// and then, it determines which version of the SDK is used by the app and what is the absolute path
 	// of its source code.
-	if err := cmdrunner.
-		New(cmdrunner.DefaultWorkdir(projectPath)).
-		Run(ctx, step.New(step.Exec("go", "mod", "download"))); err != nil {
-		return err
+	//
+	// when the app is vendored, the modules are located in its vendor dir instead, so the code can be
+	// generated without network access. their proto files are taken from the module cache when
+	// they're not vendored.
+	vendored := gomodule.IsVendored(projectPath)
+	if !vendored {
+		if err := cmdrunner.
+			New(cmdrunner.DefaultWorkdir(projectPath)).
+			Run(ctx, step.New(step.Exec("go", "mod", "download"))); err != nil {
+			return err
+		}
 	}
 
 	modfile, err := gomodule.ParseAt(projectPath)
@@ -91,25 +96,31 @@ func Generate(
 		return err
 	}
 
-	required := gomodule.FilterRequire(modfile.Require, "github.com/cosmos/cosmos-sdk")
-
-	sdkSrcPath, err := gomodule.LocatePath(required[0].Mod)
-	if err != nil {
-		return err
-	}
-
-	// add Google's and SDK's proto paths to third parties list.
+	// add Google's proto paths to third parties list.
 	protoThirdPartyPaths = append(protoThirdPartyPaths,
 		// this one should be already known by naked protoc execution, but adding it anyway to making sure.
 		os.ExpandEnv("$HOME/local/include"),
 
 		// this one is the suggested installation path for placing default proto by
 		// https://grpc.io/docs/protoc-installation/.
-		os.ExpandEnv("$HOME/.local/include"),
+		os.ExpandEnv("$HOME/.local/include"))
 
-		// sdk.
-		filepath.Join(sdkSrcPath, "proto"),
-		filepath.Join(sdkSrcPath, "third_party/proto"))
+	// add the proto paths of the SDK and the other modules required by the app.
+	for _, required := range gomodule.FilterRequire(modfile.Require, protoModules...) {
+		locate := gomodule.LocatePath
+		if vendored {
+			locate = func(pkg module.Version) (string, error) {
+				return locateVendorProto(projectPath, pkg)
+			}
+		}
+		srcPath, err := locate(required.Mod)
+		if err != nil {
+			return err
+		}
+		protoThirdPartyPaths = append(protoThirdPartyPaths,
+			filepath.Join(srcPath, "proto"),
+			filepath.Join(srcPath, "third_party/proto"))
+	}
 
 	// created a temporary dir to locate generated code under which later only some of them will be moved to the
 	// app's source code. this also prevents having leftover files in the app's source code or its parent dir -when
@@ -121,18 +132,23 @@ func Generate(
 
 	defer os.RemoveAll(tmp)
 
-	// start preparing the protoc command for execution.
-	command := []string{
-		"protoc",
-	}
-
-	// append third party proto locations to the command.
+	// the app's and third party proto locations the proto files are imported from.
+	var includePaths []string
 	for _, importPath := range append([]string{protoPath}, protoThirdPartyPaths...) {
 		// skip if a third party proto source actually doesn't exist on the filesystem.
 		if _, err := os.Stat(importPath); os.IsNotExist(err) {
 			continue
 		}
-		command = append(command, "-I", importPath)
+		includePaths = append(includePaths, importPath)
+	}
+
+	workPath := filepath.Join(tmp, "work")
+	if err := os.Mkdir(workPath, 0755); err != nil {
+		return err
+	}
+	commands, err := o.backend.Prepare(workPath, protoPath, includePaths)
+	if err != nil {
+		return err
 	}
 
 	// find out the list of proto files under the app and generate code for them.
@@ -141,47 +157,139 @@ func Generate(
 		return err
 	}
 
-	for _, file := range files {
+	c, err := readCache(o.cachePath, cacheKey(gomodPath, o.backend.Name()))
+	if err != nil {
+		return err
+	}
+	var (
+		hasher    = newProtoHasher(includePaths)
+		generated = make(map[string]cacheEntry)
+		outPaths  = make(map[string]string)
+		steps     step.Steps
+
+		failures   = make(map[string][]string)
+		failuresMu sync.Mutex
+	)
+
+	for i, file := range files {
 		// check if the file belongs to a third party proto. if so, skip it since it should
 		// only be included via `-I`.
-		var includesThirdParty bool
-		for _, protoThirdPartyPath := range protoThirdPartyPaths {
-			if strings.HasPrefix(file, protoThirdPartyPath) {
-				includesThirdParty = true
-				break
-			}
+		if isThirdParty(file, protoThirdPartyPaths) {
+			continue
+		}
+
+		rel, err := filepath.Rel(protoPath, file)
+		if err != nil {
+			return err
 		}
-		if includesThirdParty {
+		hash, err := hasher.hash(file)
+		if err != nil {
+			return err
+		}
+
+		// keep the code generated by a previous run if the file and its imports didn't change.
+		if entry, ok := c.Files[rel]; ok && entry.upToDate(projectPath, hash) {
+			generated[rel] = entry
 			continue
 		}
+		generated[rel] = cacheEntry{Hash: hash}
 
-		// run command for each protocOuts.
-		for _, out := range protocOuts {
-			command := append(command, out)
-			command = append(command, file)
+		// each file is generated to its own dir to know its generated files.
+		outPath := filepath.Join(tmp, strconv.Itoa(i))
+		if err := os.Mkdir(outPath, 0755); err != nil {
+			return err
+		}
+		outPaths[rel] = outPath
 
+		for _, command := range commands(file) {
 			errb := &bytes.Buffer{}
 
-			err := cmdrunner.
-				New(
-					cmdrunner.DefaultStderr(errb),
-					cmdrunner.DefaultWorkdir(tmp)).
-				Run(ctx,
-					step.New(step.Exec(command[0], command[1:]...)))
-
-			if err != nil {
-				return errors.Wrap(err, errb.String())
-			}
+			steps.Add(step.New(
+				step.Exec(command[0], command[1:]...),
+				step.Workdir(outPath),
+				step.Stderr(errb),
+				step.PostExec(func(err error) error {
+					// errors are aggregated per file to report all of them at once.
+					if err != nil {
+						failuresMu.Lock()
+						failures[rel] = append(failures[rel], errors.Wrap(err, errb.String()).Error())
+						failuresMu.Unlock()
+					}
+					return nil
+				}),
+			))
 		}
 	}
 
+	if err := cmdrunner.
+		New(cmdrunner.RunParallelLimit(runtime.NumCPU())).
+		Run(ctx, steps...); err != nil {
+		return err
+	}
+	if len(failures) > 0 {
+		return newGenerateError(failures)
+	}
+
 	// move generated code for the app under the relative locations in its source code.
-	generatedPath := filepath.Join(tmp, gomodPath)
-	if err := copy.Copy(generatedPath, projectPath); err != nil {
-		return errors.Wrap(err, "cannot copy path")
+	// the generated files that didn't change are left untouched so go build can keep using its cache.
+	for rel, outPath := range outPaths {
+		outputs, err := writeChanged(filepath.Join(outPath, gomodPath), projectPath)
+		if err != nil {
+			return errors.Wrap(err, "cannot copy path")
+		}
+		entry := generated[rel]
+		entry.Outputs = outputs
+		generated[rel] = entry
+	}
+
+	if o.cachePath == "" {
+		return nil
+	}
+	c.Files = generated
+	return writeCache(o.cachePath, c)
+}
+
+// locateVendorProto locates the source of pkg hosting its proto files when the module at
+// path is vendored. go mod vendor doesn't copy the proto files, so they're taken from the
+// module cache unless they're vendored too.
+func locateVendorProto(path string, pkg module.Version) (string, error) {
+	vendorPath, _ := gomodule.LocateVendorPath(path, pkg)
+	if _, err := os.Stat(filepath.Join(vendorPath, "proto")); err == nil {
+		return vendorPath, nil
+	}
+	cachePath, _ := gomodule.LocatePath(pkg)
+	if _, err := os.Stat(filepath.Join(cachePath, "proto")); err == nil {
+		return cachePath, nil
+	}
+	return "", fmt.Errorf("cannot find the proto files of %s@%s: neither %s nor %s exists",
+		pkg.Path, pkg.Version, filepath.Join(vendorPath, "proto"), filepath.Join(cachePath, "proto"))
+}
+
+// GenerateError is returned when the code of some proto files can't be generated.
+type GenerateError struct {
+	// Files maps the paths of the proto files relative to the proto path to their errors.
+	Files map[string][]string
+}
+
+func newGenerateError(failures map[string][]string) *GenerateError {
+	return &GenerateError{Files: failures}
+}
+
+func (e *GenerateError) Error() string {
+	var files []string
+	for file := range e.Files {
+		files = append(files, file)
 	}
+	sort.Strings(files)
 
-	return nil
+	var b strings.Builder
+	for _, file := range files {
+		fmt.Fprintf(&b, "%s:\n", file)
+		for _, err := range e.Files[file] {
+			fmt.Fprintf(&b, "  %s\n", strings.TrimSpace(err))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
 }
 
 func globProto(path string) string { return path + "/**/*.proto" }
@@ -0,0 +1,51 @@
+package cosmosprotoc
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/module"
+)
+
+func TestGenerateError(t *testing.T) {
+	err := newGenerateError(map[string][]string{
+		"foo/b.proto": {"b.proto:3:1: Expected \";\".\n"},
+		"foo/a.proto": {"a.proto: File not found.", "a.proto: File not found."},
+	})
+	require.Equal(t, `foo/a.proto:
+  a.proto: File not found.
+  a.proto: File not found.
+foo/b.proto:
+  b.proto:3:1: Expected ";".`, err.Error())
+}
+
+func TestLocateVendorProto(t *testing.T) {
+	gopath := build.Default.GOPATH
+	defer func() { build.Default.GOPATH = gopath }()
+	build.Default.GOPATH = t.TempDir()
+
+	appPath := t.TempDir()
+	pkg := module.Version{Path: "github.com/cosmos/cosmos-sdk", Version: "v0.42.1"}
+	vendorPath := filepath.Join(appPath, "vendor", "github.com/cosmos/cosmos-sdk")
+	cachePath := filepath.Join(build.Default.GOPATH, "pkg/mod", "github.com/cosmos/cosmos-sdk@v0.42.1")
+
+	// go mod vendor only copies the Go packages of the SDK.
+	require.NoError(t, os.MkdirAll(filepath.Join(vendorPath, "types"), 0755))
+	_, err := locateVendorProto(appPath, pkg)
+	require.EqualError(t, err, fmt.Sprintf("cannot find the proto files of github.com/cosmos/cosmos-sdk@v0.42.1: neither %s nor %s exists",
+		filepath.Join(vendorPath, "proto"), filepath.Join(cachePath, "proto")))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(cachePath, "proto"), 0755))
+	path, err := locateVendorProto(appPath, pkg)
+	require.NoError(t, err)
+	require.Equal(t, cachePath, path)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(vendorPath, "proto"), 0755))
+	path, err = locateVendorProto(appPath, pkg)
+	require.NoError(t, err)
+	require.Equal(t, vendorPath, path)
+}
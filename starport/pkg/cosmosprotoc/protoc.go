@@ -0,0 +1,56 @@
+package cosmosprotoc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/starport/starport/pkg/toolcache"
+	"github.com/tendermint/starport/starport/pkg/xexec"
+)
+
+// ErrProtocNotInstalled is returned when protoc isn't installed on the system.
+var ErrProtocNotInstalled = errors.New("protoc is not installed")
+
+// Protoc is the backend generating code with the protoc installed on the system.
+type Protoc struct {
+	tools toolcache.Cache
+}
+
+// NewProtoc returns the protoc backend using the plugins built into tools.
+func NewProtoc(tools toolcache.Cache) Protoc {
+	return Protoc{tools}
+}
+
+// Name implements Backend.
+func (Protoc) Name() string { return BackendProtoc }
+
+// InstallDependencies installs protoc dependencies needed by Cosmos ecosystem.
+func (p Protoc) InstallDependencies(ctx context.Context) error {
+	if !xexec.IsCommandAvailable("protoc") {
+		return ErrProtocNotInstalled
+	}
+	return installPlugins(ctx, p.tools)
+}
+
+// Prepare implements Backend.
+func (p Protoc) Prepare(workPath, protoPath string, includePaths []string) (func(path string) [][]string, error) {
+	var includes []string
+	for _, includePath := range includePaths {
+		includes = append(includes, "-I", includePath)
+	}
+	for _, plugin := range plugins {
+		includes = append(includes, fmt.Sprintf("--plugin=protoc-gen-%s=%s", plugin.name, p.tools.BinaryPath(plugin.tool)))
+	}
+
+	return func(path string) [][]string {
+		// protoc runs for each plugin.
+		var commands [][]string
+		for _, plugin := range plugins {
+			command := append([]string{"protoc"}, includes...)
+			command = append(command, fmt.Sprintf("--%s_out=%s:.", plugin.name, plugin.opt), path)
+			commands = append(commands, command)
+		}
+		return commands
+	}, nil
+}
@@ -15,11 +15,16 @@ var ErrNoFile = errors.New("no file in specified paths")
 // If checksumSavePath directory doesn't exist, it is created
 // paths are relative to workdir, if workdir is empty string paths are absolute
 func SaveDirChecksum(workdir string, paths []string, checksumSavePath string, checksumName string) error {
-	checksum, err := checksumFromPaths(workdir, paths)
+	checksum, err := ChecksumFromPaths(workdir, paths)
 	if err != nil {
 		return err
 	}
+	return SaveChecksum(checksum, checksumSavePath, checksumName)
+}
 
+// SaveChecksum saves checksum in the specified directory
+// If checksumSavePath directory doesn't exist, it is created
+func SaveChecksum(checksum []byte, checksumSavePath string, checksumName string) error {
 	// create directory if needed
 	if err := os.MkdirAll(checksumSavePath, 0700); err != nil && !os.IsExist(err) {
 		return err
@@ -47,7 +52,7 @@ func HasDirChecksumChanged(workdir string, paths []string, checksumSavePath stri
 	}
 
 	// Compute checksum
-	checksum, err := checksumFromPaths(workdir, paths)
+	checksum, err := ChecksumFromPaths(workdir, paths)
 	if errors.Is(err, ErrNoFile) {
 		// Checksum cannot be saved with no file
 		// Therefore if no file are found, this means these have been delete, then the directory has been changed
@@ -56,22 +61,33 @@ func HasDirChecksumChanged(workdir string, paths []string, checksumSavePath stri
 		return false, err
 	}
 
-	// Compare checksums
-	savedChecksum, err := ioutil.ReadFile(checksumFilePath)
-	if err != nil {
+	return HasChecksumChanged(checksum, checksumSavePath, checksumName)
+}
+
+// HasChecksumChanged compares checksum with the checksum saved in the specified directory
+// Return true if the checksum file doesn't exist yet
+func HasChecksumChanged(checksum []byte, checksumSavePath string, checksumName string) (bool, error) {
+	savedChecksum, err := ioutil.ReadFile(filepath.Join(checksumSavePath, checksumName))
+	if os.IsNotExist(err) {
+		return true, nil
+	} else if err != nil {
 		return false, err
 	}
-	if bytes.Equal(checksum, savedChecksum) {
-		return false, nil
-	}
 
-	// The checksum has changed
-	return true, nil
+	// The checksum has changed if it's different from the saved one
+	return !bytes.Equal(checksum, savedChecksum), nil
+}
+
+// ChecksumFromPaths computes the md5 checksum from the provided paths (directories or files)
+// paths are relative to workdir, if workdir is empty string paths are absolute
+func ChecksumFromPaths(workdir string, paths []string) ([]byte, error) {
+	return ChecksumFromPathsFunc(workdir, paths, nil)
 }
 
-// checksumFromPaths computes the md5 checksum from the provided paths
+// ChecksumFromPathsFunc computes the md5 checksum from the files of the provided paths (directories or files)
+// include filters the files of the checksum by their paths, all the files are included if include is nil
 // paths are relative to workdir, if workdir is empty string paths are absolute
-func checksumFromPaths(workdir string, paths []string) ([]byte, error) {
+func ChecksumFromPathsFunc(workdir string, paths []string, include func(path string) bool) ([]byte, error) {
 	hash := md5.New()
 
 	// Can't compute hash if no file present
@@ -95,8 +111,8 @@ func checksumFromPaths(workdir string, paths []string) ([]byte, error) {
 				return err
 			}
 
-			// ignore directory
-			if info.IsDir() {
+			// ignore directory and excluded files
+			if info.IsDir() || (include != nil && !include(subPath)) {
 				return nil
 			}
 
@@ -60,7 +60,7 @@ func TestHasDirChecksumChanged(t *testing.T) {
 
 	// Check checksum
 	paths := []string{dir1, dir2, dir3}
-	checksum, err := checksumFromPaths("", paths)
+	checksum, err := ChecksumFromPaths("", paths)
 	require.NoError(t, err)
 	// md5 checksum is 16 bytes
 	require.Len(t, checksum, 16)
@@ -70,31 +70,31 @@ func TestHasDirChecksumChanged(t *testing.T) {
 	require.NoError(t, err)
 	err = ioutil.WriteFile(filepath.Join(dir1, "foo"), []byte("some bytes"), 0644)
 	require.NoError(t, err)
-	tmpChecksum, err := checksumFromPaths("", paths)
+	tmpChecksum, err := ChecksumFromPaths("", paths)
 	require.NoError(t, err)
 	require.Equal(t, checksum, tmpChecksum)
 
 	// Can compute the checksum from a specific workdir
 	pathNames := []string{"foo1", "foo2", "foo3"}
-	tmpChecksum, err = checksumFromPaths(tempDir, pathNames)
+	tmpChecksum, err = ChecksumFromPaths(tempDir, pathNames)
 	require.NoError(t, err)
 	require.Equal(t, checksum, tmpChecksum)
 
 	// Ignore non existent dir
 	pathNames = append(pathNames, "nonexistent")
-	tmpChecksum, err = checksumFromPaths(tempDir, pathNames)
+	tmpChecksum, err = ChecksumFromPaths(tempDir, pathNames)
 	require.NoError(t, err)
 	require.Equal(t, checksum, tmpChecksum)
 
 	// Checksum from a subdir is different
-	tmpChecksum, err = checksumFromPaths("", []string{dir1, dir2})
+	tmpChecksum, err = ChecksumFromPaths("", []string{dir1, dir2})
 	require.NoError(t, err)
 	require.NotEqual(t, checksum, tmpChecksum)
 
 	// Checksum changes if a file is modified
 	err = ioutil.WriteFile(filepath.Join(dir3, "foo1"), randomBytes(10), 0644)
 	require.NoError(t, err)
-	newChecksum, err := checksumFromPaths("", paths)
+	newChecksum, err := ChecksumFromPaths("", paths)
 	require.NoError(t, err)
 	require.NotEqual(t, checksum, newChecksum)
 
@@ -107,7 +107,7 @@ func TestHasDirChecksumChanged(t *testing.T) {
 	err = os.MkdirAll(empty2, 0700)
 	require.NoError(t, err)
 	defer os.RemoveAll(empty2)
-	_, err = checksumFromPaths("", []string{empty1, empty2})
+	_, err = ChecksumFromPaths("", []string{empty1, empty2})
 	require.Error(t, err)
 
 	// SaveDirChecksum saves the checksum in the specified dir
@@ -150,3 +150,51 @@ func TestHasDirChecksumChanged(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, changed)
 }
+
+func TestChecksumFromPathsFunc(t *testing.T) {
+	dir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "foo.go"), []byte("package foo"), 0644)
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(dir, "foo.js"), []byte("foo()"), 0644)
+	require.NoError(t, err)
+
+	isGo := func(path string) bool { return filepath.Ext(path) == ".go" }
+	checksum, err := ChecksumFromPathsFunc(dir, []string{"."}, isGo)
+	require.NoError(t, err)
+
+	// Checksum doesn't change if an excluded file is modified
+	err = ioutil.WriteFile(filepath.Join(dir, "foo.js"), []byte("bar()"), 0644)
+	require.NoError(t, err)
+	tmpChecksum, err := ChecksumFromPathsFunc(dir, []string{"."}, isGo)
+	require.NoError(t, err)
+	require.Equal(t, checksum, tmpChecksum)
+
+	// Checksum includes all the files without filter
+	tmpChecksum, err = ChecksumFromPathsFunc(dir, []string{"."}, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, checksum, tmpChecksum)
+
+	// Error if no file is included
+	_, err = ChecksumFromPathsFunc(dir, []string{"."}, func(string) bool { return false })
+	require.Equal(t, ErrNoFile, err)
+}
+
+func TestHasChecksumChanged(t *testing.T) {
+	saveDir := t.TempDir()
+
+	// Return true if checksum file doesn't exist
+	changed, err := HasChecksumChanged([]byte("foo"), saveDir, ChecksumFile)
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	err = SaveChecksum([]byte("foo"), saveDir, ChecksumFile)
+	require.NoError(t, err)
+
+	changed, err = HasChecksumChanged([]byte("foo"), saveDir, ChecksumFile)
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	changed, err = HasChecksumChanged([]byte("bar"), saveDir, ChecksumFile)
+	require.NoError(t, err)
+	require.True(t, changed)
+}
@@ -0,0 +1,61 @@
+// Package gitmerge merges the changes made to a file from a common base by using git merge-file.
+package gitmerge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// ErrConflict is returned when the changes to merge conflict.
+var ErrConflict = errors.New("merge conflict")
+
+// Merge does a three-way merge of the changes made from base to current and from base to other.
+// when the changes conflict, the merged content with conflict markers is returned together with ErrConflict.
+func Merge(ctx context.Context, current, base, other []byte) ([]byte, error) {
+	dir, err := ioutil.TempDir("", "starport-gitmerge")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{"current", current},
+		{"base", base},
+		{"other", other},
+	}
+	args := []string{"merge-file", "-p", "-L", "current", "-L", "base", "-L", "new"}
+	for _, file := range files {
+		path := filepath.Join(dir, file.name)
+		if err := ioutil.WriteFile(path, file.content, 0644); err != nil {
+			return nil, err
+		}
+		args = append(args, path)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+
+	// git merge-file exits with the number of conflicts, or a negative code on error
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return stdout.Bytes(), nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128:
+		return stdout.Bytes(), ErrConflict
+	case stderr.Len() > 0:
+		return nil, errors.New(stderr.String())
+	default:
+		return nil, err
+	}
+}
@@ -0,0 +1,29 @@
+package gitmerge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	base := []byte("a\nb\nc\nd\ne\n")
+	current := []byte("a\nb2\nc\nd\ne\n")
+	other := []byte("a\nb\nc\nd\ne2\n")
+
+	merged, err := Merge(context.Background(), current, base, other)
+	require.NoError(t, err)
+	require.Equal(t, "a\nb2\nc\nd\ne2\n", string(merged))
+}
+
+func TestMergeConflict(t *testing.T) {
+	base := []byte("a\nb\nc\n")
+	current := []byte("a\nb2\nc\n")
+	other := []byte("a\nb3\nc\n")
+
+	merged, err := Merge(context.Background(), current, base, other)
+	require.Equal(t, ErrConflict, err)
+	require.Contains(t, string(merged), "<<<<<<< current")
+	require.Contains(t, string(merged), ">>>>>>> new")
+}
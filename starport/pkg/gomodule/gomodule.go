@@ -3,9 +3,14 @@ package gomodule
 import (
 	"fmt"
 	"go/build"
+	"go/parser"
+	"go/token"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"golang.org/x/mod/modfile"
 	"golang.org/x/mod/module"
@@ -42,3 +47,79 @@ func LocatePath(pkg module.Version) (path string, err error) {
 	_, err = os.Stat(path)
 	return
 }
+
+const (
+	// ModReadonly is the -mod flag of the builds using the module cache without updating go.mod.
+	ModReadonly = "readonly"
+
+	// ModVendor is the -mod flag of the builds using the vendor dir of the module.
+	ModVendor = "vendor"
+)
+
+// ModFlag returns the -mod flag to build the module at path with. the flag set in GOFLAGS is
+// used if any, the vendor dir is used when the module has a vendor/modules.txt, and the module
+// cache otherwise.
+func ModFlag(path string) string {
+	for _, flag := range strings.Fields(os.Getenv("GOFLAGS")) {
+		if strings.HasPrefix(flag, "-mod=") {
+			return strings.TrimPrefix(flag, "-mod=")
+		}
+	}
+	if _, err := os.Stat(filepath.Join(path, "vendor", "modules.txt")); err == nil {
+		return ModVendor
+	}
+	return ModReadonly
+}
+
+// IsVendored checks if the module at path is built from its vendor dir.
+func IsVendored(path string) bool {
+	return ModFlag(path) == ModVendor
+}
+
+// LocateVendorPath locates pkg's absolute path in the vendor dir of the module at path.
+// note that go mod vendor only copies the packages imported by the module, files like
+// proto files are only found if they're vendored too.
+func LocateVendorPath(path string, pkg module.Version) (string, error) {
+	vendorPath := filepath.Join(path, "vendor", filepath.FromSlash(pkg.Path))
+	_, err := os.Stat(vendorPath)
+	return vendorPath, err
+}
+
+// Imports returns the sorted import paths of the Go files under paths, the paths that don't
+// exist are ignored. go mod tidy only updates go.mod when these imports change.
+func Imports(paths ...string) ([]string, error) {
+	set := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil || info.IsDir() || filepath.Ext(path) != ".go" {
+				return err
+			}
+			file, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+			if err != nil {
+				return err
+			}
+			for _, spec := range file.Imports {
+				importPath, err := strconv.Unquote(spec.Path.Value)
+				if err != nil {
+					return err
+				}
+				set[importPath] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	imports := make([]string, 0, len(set))
+	for importPath := range set {
+		imports = append(imports, importPath)
+	}
+	sort.Strings(imports)
+	return imports, nil
+}
@@ -0,0 +1,66 @@
+package gomodule
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/module"
+)
+
+func TestModFlag(t *testing.T) {
+	path := t.TempDir()
+	defer os.Setenv("GOFLAGS", os.Getenv("GOFLAGS"))
+	os.Setenv("GOFLAGS", "")
+	require.Equal(t, ModReadonly, ModFlag(path))
+
+	require.NoError(t, os.Mkdir(filepath.Join(path, "vendor"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, "vendor", "modules.txt"), nil, 0644))
+	require.Equal(t, ModVendor, ModFlag(path))
+	require.True(t, IsVendored(path))
+
+	// the flag set in GOFLAGS is preferred.
+	os.Setenv("GOFLAGS", "-trimpath -mod=mod")
+	require.Equal(t, "mod", ModFlag(path))
+	require.False(t, IsVendored(path))
+}
+
+func TestLocateVendorPath(t *testing.T) {
+	var (
+		path = t.TempDir()
+		pkg  = module.Version{Path: "github.com/cosmos/cosmos-sdk", Version: "v0.42.0"}
+	)
+	_, err := LocateVendorPath(path, pkg)
+	require.Error(t, err)
+
+	expected := filepath.Join(path, "vendor", "github.com", "cosmos", "cosmos-sdk")
+	require.NoError(t, os.MkdirAll(expected, 0755))
+	vendorPath, err := LocateVendorPath(path, pkg)
+	require.NoError(t, err)
+	require.Equal(t, expected, vendorPath)
+}
+
+func TestImports(t *testing.T) {
+	path := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(path, "x"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, "x", "a.go"), []byte(`package x
+
+import (
+	"fmt"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, "x", "b.go"), []byte(`package x
+
+import "fmt"
+
+func b() { fmt.Println() }
+`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, "x", "c.js"), []byte(`import "vue"`), 0644))
+
+	imports, err := Imports(filepath.Join(path, "x"), filepath.Join(path, "nonexistent"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"fmt", "github.com/cosmos/cosmos-sdk/types"}, imports)
+}
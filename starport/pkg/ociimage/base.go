@@ -0,0 +1,48 @@
+package ociimage
+
+import (
+	"sort"
+
+	"github.com/gobuffalo/packr/v2"
+)
+
+const (
+	// NonrootUID and NonrootGID are the ids of the nonroot user of the base layer.
+	NonrootUID = 65532
+	NonrootGID = 65532
+
+	// NonrootHome is the home of the nonroot user of the base layer.
+	NonrootHome = "/home/nonroot"
+)
+
+// base holds the files of the base layer: the users and groups, the name service config
+// and the CA certificates of a distroless static image.
+var base = packr.New("ociimage/base", "./base")
+
+// BaseLayer returns a distroless-style base layer for static binaries, with the root, nobody
+// and nonroot users, CA certificates, a /tmp dir and the home of the nonroot user.
+func BaseLayer() (Layer, error) {
+	layer := Layer{
+		{Path: "/etc", Mode: 0755, Dir: true},
+		{Path: "/etc/ssl", Mode: 0755, Dir: true},
+		{Path: "/etc/ssl/certs", Mode: 0755, Dir: true},
+		{Path: "/home", Mode: 0755, Dir: true},
+		{Path: NonrootHome, Mode: 0700, UID: NonrootUID, GID: NonrootGID, Dir: true},
+		{Path: "/root", Mode: 0700, Dir: true},
+		{Path: "/tmp", Mode: 01777, Dir: true},
+		{Path: "/usr", Mode: 0755, Dir: true},
+		{Path: "/usr/local", Mode: 0755, Dir: true},
+		{Path: "/usr/local/bin", Mode: 0755, Dir: true},
+	}
+
+	names := base.List()
+	sort.Strings(names)
+	for _, name := range names {
+		content, err := base.Find(name)
+		if err != nil {
+			return nil, err
+		}
+		layer = append(layer, File{Path: "/" + name, Mode: 0644, Content: content})
+	}
+	return layer, nil
+}
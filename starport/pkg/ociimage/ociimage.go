@@ -0,0 +1,400 @@
+// Package ociimage builds container images as OCI image layout tarballs without a container
+// engine. the tarballs can be pushed with tools like skopeo or crane, and loaded with docker load.
+package ociimage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	mediaTypeIndex    = "application/vnd.oci.image.index.v1+json"
+	mediaTypeManifest = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeConfig   = "application/vnd.oci.image.config.v1+json"
+	mediaTypeLayer    = "application/vnd.oci.image.layer.v1.tar+gzip"
+
+	annotationRefName       = "org.opencontainers.image.ref.name"
+	annotationContainerdRef = "io.containerd.image.name"
+)
+
+// epoch is the modification time of the files of the images, so images built from the same
+// files are the same.
+var epoch = time.Unix(0, 0).UTC()
+
+// File is a file or a dir of a layer.
+type File struct {
+	// Path is the absolute path of the file in the image.
+	Path string
+
+	// Mode holds the permission bits of the file.
+	Mode int64
+
+	// UID and GID own the file.
+	UID, GID int
+
+	// Dir is true for dirs.
+	Dir bool
+
+	// Content is the content of the file, it's read from SourcePath when SourcePath is set.
+	Content []byte
+
+	// SourcePath is the path of a file on the local filesystem to read the content from.
+	SourcePath string
+}
+
+// Layer is a layer of an image.
+type Layer []File
+
+// Config is the configuration of the containers run from an image.
+type Config struct {
+	// OS and Architecture are the platform of the image, e.g.: linux and amd64.
+	OS           string
+	Architecture string
+
+	User       string
+	Env        []string
+	Entrypoint []string
+	Cmd        []string
+	WorkingDir string
+
+	// ExposedPorts holds the ports of the image, e.g.: 26657/tcp.
+	ExposedPorts []string
+
+	// Volumes holds the dirs of the image holding data, e.g.: the home of a node.
+	Volumes []string
+
+	Labels map[string]string
+}
+
+// ParseReference parses ref in the name:tag format, the latest tag is used when it's not set.
+func ParseReference(ref string) (name, tag string, err error) {
+	name, tag = ref, "latest"
+	// the port of a registry is separated by a colon too, e.g.: localhost:5000/app.
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		name, tag = ref[:i], ref[i+1:]
+	}
+	if name == "" || tag == "" || strings.ContainsAny(ref, " @") {
+		return "", "", fmt.Errorf("invalid image reference %s, use the name:tag format, e.g.: mars:v0.1.0", ref)
+	}
+	return name, tag, nil
+}
+
+// descriptor describes a blob of an image.
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Write writes the OCI image layout tarball of the image made of layers and config, tagged
+// as ref, to w.
+func Write(w io.Writer, ref string, config Config, layers ...Layer) error {
+	name, tag, err := ParseReference(ref)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempDir("", "starport-image")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	tw := tar.NewWriter(w)
+
+	// the layers are written to disk first, their digests are needed to write them to the tarball.
+	var (
+		layerDescriptors []descriptor
+		diffIDs          []string
+	)
+	for i, layer := range layers {
+		layerPath := filepath.Join(tmp, fmt.Sprintf("layer%d.tar.gz", i))
+		desc, diffID, err := writeLayer(layerPath, layer)
+		if err != nil {
+			return err
+		}
+		if err := addBlobFile(tw, desc.Digest, layerPath, desc.Size); err != nil {
+			return err
+		}
+		layerDescriptors = append(layerDescriptors, desc)
+		diffIDs = append(diffIDs, diffID)
+	}
+
+	configBlob, err := json.Marshal(imageConfig(config, diffIDs))
+	if err != nil {
+		return err
+	}
+	configDescriptor, err := addBlob(tw, mediaTypeConfig, configBlob)
+	if err != nil {
+		return err
+	}
+
+	manifestBlob, err := json.Marshal(struct {
+		SchemaVersion int          `json:"schemaVersion"`
+		MediaType     string       `json:"mediaType"`
+		Config        descriptor   `json:"config"`
+		Layers        []descriptor `json:"layers"`
+	}{2, mediaTypeManifest, configDescriptor, layerDescriptors})
+	if err != nil {
+		return err
+	}
+	manifestDescriptor, err := addBlob(tw, mediaTypeManifest, manifestBlob)
+	if err != nil {
+		return err
+	}
+	manifestDescriptor.Annotations = map[string]string{
+		annotationRefName:       tag,
+		annotationContainerdRef: name + ":" + tag,
+	}
+
+	index, err := json.Marshal(struct {
+		SchemaVersion int          `json:"schemaVersion"`
+		MediaType     string       `json:"mediaType"`
+		Manifests     []descriptor `json:"manifests"`
+	}{2, mediaTypeIndex, []descriptor{manifestDescriptor}})
+	if err != nil {
+		return err
+	}
+
+	// docker load reads the manifest.json of the images saved by docker.
+	var layerPaths []string
+	for _, desc := range layerDescriptors {
+		layerPaths = append(layerPaths, blobPath(desc.Digest))
+	}
+	dockerManifest, err := json.Marshal([]struct {
+		Config   string
+		RepoTags []string
+		Layers   []string
+	}{{blobPath(configDescriptor.Digest), []string{name + ":" + tag}, layerPaths}})
+	if err != nil {
+		return err
+	}
+
+	for _, file := range []struct {
+		name    string
+		content []byte
+	}{
+		{"oci-layout", []byte(`{"imageLayoutVersion":"1.0.0"}`)},
+		{"index.json", index},
+		{"manifest.json", dockerManifest},
+	} {
+		if err := addFile(tw, file.name, file.content); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// imageConfig returns the OCI image config of the image with the layers of diffIDs.
+func imageConfig(config Config, diffIDs []string) interface{} {
+	type containerConfig struct {
+		User         string              `json:"User,omitempty"`
+		ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+		Env          []string            `json:"Env,omitempty"`
+		Entrypoint   []string            `json:"Entrypoint,omitempty"`
+		Cmd          []string            `json:"Cmd,omitempty"`
+		Volumes      map[string]struct{} `json:"Volumes,omitempty"`
+		WorkingDir   string              `json:"WorkingDir,omitempty"`
+		Labels       map[string]string   `json:"Labels,omitempty"`
+	}
+	type rootfs struct {
+		Type    string   `json:"type"`
+		DiffIDs []string `json:"diff_ids"`
+	}
+
+	set := func(keys []string) map[string]struct{} {
+		if len(keys) == 0 {
+			return nil
+		}
+		m := make(map[string]struct{})
+		for _, key := range keys {
+			m[key] = struct{}{}
+		}
+		return m
+	}
+
+	return struct {
+		Created      time.Time       `json:"created"`
+		Architecture string          `json:"architecture"`
+		OS           string          `json:"os"`
+		Config       containerConfig `json:"config"`
+		RootFS       rootfs          `json:"rootfs"`
+	}{
+		Created:      epoch,
+		Architecture: config.Architecture,
+		OS:           config.OS,
+		Config: containerConfig{
+			User:         config.User,
+			ExposedPorts: set(config.ExposedPorts),
+			Env:          config.Env,
+			Entrypoint:   config.Entrypoint,
+			Cmd:          config.Cmd,
+			Volumes:      set(config.Volumes),
+			WorkingDir:   config.WorkingDir,
+			Labels:       config.Labels,
+		},
+		RootFS: rootfs{"layers", diffIDs},
+	}
+}
+
+// writeLayer writes the gzipped tarball of layer to path. it returns the descriptor of the
+// tarball and the digest of the uncompressed tarball.
+func writeLayer(path string, layer Layer) (desc descriptor, diffID string, err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return descriptor{}, "", err
+	}
+	defer file.Close()
+
+	var (
+		digest  = sha256.New()
+		counter = &countWriter{w: io.MultiWriter(file, digest)}
+		gzw     = gzip.NewWriter(counter)
+		diffSum = sha256.New()
+		tw      = tar.NewWriter(io.MultiWriter(gzw, diffSum))
+	)
+
+	// parent dirs come first in the tarball.
+	files := append(Layer{}, layer...)
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	for _, f := range files {
+		if err := addLayerFile(tw, f); err != nil {
+			return descriptor{}, "", err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return descriptor{}, "", err
+	}
+	if err := gzw.Close(); err != nil {
+		return descriptor{}, "", err
+	}
+
+	return descriptor{
+		MediaType: mediaTypeLayer,
+		Digest:    digestOf(digest),
+		Size:      counter.n,
+	}, digestOf(diffSum), nil
+}
+
+func addLayerFile(tw *tar.Writer, f File) error {
+	header := &tar.Header{
+		Name:    strings.TrimPrefix(path.Clean(f.Path), "/"),
+		Mode:    f.Mode,
+		Uid:     f.UID,
+		Gid:     f.GID,
+		ModTime: epoch,
+		Format:  tar.FormatPAX,
+	}
+	if f.Dir {
+		header.Typeflag = tar.TypeDir
+		header.Name += "/"
+		return tw.WriteHeader(header)
+	}
+
+	header.Typeflag = tar.TypeReg
+	if f.SourcePath == "" {
+		header.Size = int64(len(f.Content))
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(f.Content)
+		return err
+	}
+
+	source, err := os.Open(f.SourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	info, err := source.Stat()
+	if err != nil {
+		return err
+	}
+	header.Size = info.Size()
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, source)
+	return err
+}
+
+// addBlob adds content as a blob of mediaType to the image layout.
+func addBlob(tw *tar.Writer, mediaType string, content []byte) (descriptor, error) {
+	digest := sha256.Sum256(content)
+	desc := descriptor{
+		MediaType: mediaType,
+		Digest:    "sha256:" + hex.EncodeToString(digest[:]),
+		Size:      int64(len(content)),
+	}
+	return desc, addFile(tw, blobPath(desc.Digest), content)
+}
+
+// addBlobFile adds the file at path as the blob with digest to the image layout.
+func addBlobFile(tw *tar.Writer, digest, path string, size int64) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    blobPath(digest),
+		Mode:    0644,
+		Size:    size,
+		ModTime: epoch,
+	}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, file)
+	return err
+}
+
+func addFile(tw *tar.Writer, name string, content []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: epoch,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
+// blobPath returns the path of the blob with digest in the image layout.
+func blobPath(digest string) string {
+	return "blobs/" + strings.Replace(digest, ":", "/", 1)
+}
+
+func digestOf(h hash.Hash) string {
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// countWriter counts the bytes written to w.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
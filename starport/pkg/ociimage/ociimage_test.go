@@ -0,0 +1,164 @@
+package ociimage
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	for ref, expected := range map[string][2]string{
+		"mars":                     {"mars", "latest"},
+		"mars:v0.1.0":              {"mars", "v0.1.0"},
+		"localhost:5000/mars":      {"localhost:5000/mars", "latest"},
+		"localhost:5000/mars:v0.1": {"localhost:5000/mars", "v0.1"},
+	} {
+		name, tag, err := ParseReference(ref)
+		require.NoError(t, err)
+		require.Equal(t, expected, [2]string{name, tag})
+	}
+
+	for _, ref := range []string{"", ":v0.1.0", "mars:", "mars@sha256:abc"} {
+		_, _, err := ParseReference(ref)
+		require.Error(t, err, ref)
+	}
+}
+
+// readTar reads the files of a tarball.
+func readTar(t *testing.T, r io.Reader) map[string]*tarFile {
+	files := make(map[string]*tarFile)
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = &tarFile{header, content}
+	}
+}
+
+type tarFile struct {
+	header  *tar.Header
+	content []byte
+}
+
+func readJSON(t *testing.T, files map[string]*tarFile, name string, v interface{}) {
+	file, ok := files[name]
+	require.True(t, ok, name)
+	require.NoError(t, json.Unmarshal(file.content, v))
+}
+
+func sha256Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestWrite(t *testing.T) {
+	binaryPath := filepath.Join(t.TempDir(), "marsd")
+	require.NoError(t, ioutil.WriteFile(binaryPath, []byte("binary"), 0755))
+
+	base, err := BaseLayer()
+	require.NoError(t, err)
+	app := Layer{
+		{Path: "/usr/local/bin/marsd", Mode: 0755, SourcePath: binaryPath},
+		{Path: "/home/nonroot/.mars", Mode: 0700, UID: NonrootUID, GID: NonrootGID, Dir: true},
+	}
+	config := Config{
+		OS:           "linux",
+		Architecture: "amd64",
+		User:         "65532:65532",
+		Entrypoint:   []string{"/usr/local/bin/marsd"},
+		Cmd:          []string{"start"},
+		ExposedPorts: []string{"26657/tcp"},
+		Volumes:      []string{"/home/nonroot/.mars"},
+	}
+
+	var image bytes.Buffer
+	require.NoError(t, Write(&image, "mars:v0.1.0", config, base, app))
+
+	// the same image is built from the same files.
+	var again bytes.Buffer
+	require.NoError(t, Write(&again, "mars:v0.1.0", config, base, app))
+	require.Equal(t, image.Bytes(), again.Bytes())
+
+	files := readTar(t, &image)
+
+	var index struct {
+		Manifests []descriptor `json:"manifests"`
+	}
+	readJSON(t, files, "index.json", &index)
+	require.Len(t, index.Manifests, 1)
+	require.Equal(t, mediaTypeManifest, index.Manifests[0].MediaType)
+	require.Equal(t, "v0.1.0", index.Manifests[0].Annotations[annotationRefName])
+
+	// the blobs are found by their digests.
+	for name, file := range files {
+		if filepath.Dir(name) == "blobs/sha256" {
+			require.Equal(t, "sha256:"+filepath.Base(name), sha256Digest(file.content))
+		}
+	}
+
+	var manifest struct {
+		Config descriptor   `json:"config"`
+		Layers []descriptor `json:"layers"`
+	}
+	readJSON(t, files, blobPath(index.Manifests[0].Digest), &manifest)
+	require.Len(t, manifest.Layers, 2)
+
+	var imageConfig struct {
+		OS     string `json:"os"`
+		Config struct {
+			User         string
+			Entrypoint   []string
+			ExposedPorts map[string]struct{}
+			Volumes      map[string]struct{}
+		} `json:"config"`
+		RootFS struct {
+			DiffIDs []string `json:"diff_ids"`
+		} `json:"rootfs"`
+	}
+	readJSON(t, files, blobPath(manifest.Config.Digest), &imageConfig)
+	require.Equal(t, "linux", imageConfig.OS)
+	require.Equal(t, "65532:65532", imageConfig.Config.User)
+	require.Equal(t, config.Entrypoint, imageConfig.Config.Entrypoint)
+	require.Contains(t, imageConfig.Config.ExposedPorts, "26657/tcp")
+	require.Contains(t, imageConfig.Config.Volumes, "/home/nonroot/.mars")
+
+	// the diff ids are the digests of the uncompressed layers.
+	layers := make([]map[string]*tarFile, len(manifest.Layers))
+	for i, layer := range manifest.Layers {
+		gzr, err := gzip.NewReader(bytes.NewReader(files[blobPath(layer.Digest)].content))
+		require.NoError(t, err)
+		uncompressed, err := ioutil.ReadAll(gzr)
+		require.NoError(t, err)
+		require.Equal(t, imageConfig.RootFS.DiffIDs[i], sha256Digest(uncompressed))
+		layers[i] = readTar(t, bytes.NewReader(uncompressed))
+	}
+
+	require.Contains(t, string(layers[0]["etc/passwd"].content), "nonroot:x:65532:65532")
+	require.Contains(t, layers[0], "etc/ssl/certs/ca-certificates.crt")
+	require.Equal(t, int64(01777), layers[0]["tmp/"].header.Mode)
+
+	binary := layers[1]["usr/local/bin/marsd"]
+	require.Equal(t, "binary", string(binary.content))
+	require.Equal(t, int64(0755), binary.header.Mode)
+	require.Equal(t, NonrootUID, layers[1]["home/nonroot/.mars/"].header.Uid)
+
+	var dockerManifest []struct {
+		RepoTags []string
+	}
+	readJSON(t, files, "manifest.json", &dockerManifest)
+	require.Equal(t, []string{"mars:v0.1.0"}, dockerManifest[0].RepoTags)
+}
@@ -0,0 +1,175 @@
+package protocheck
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Breaking returns the wire-breaking changes made to the messages and enums of previous in
+// current: removed messages, enums, fields and enum values, renumbered fields and fields
+// that changed type. removing a field or an enum value is safe when its number is reserved.
+func Breaking(previous, current []*File) []Issue {
+	var (
+		issues          []Issue
+		currentMessages = messagesByName(current)
+		currentEnums    = enumsByName(current)
+	)
+
+	for _, f := range previous {
+		issue := func(line int, format string, args ...interface{}) {
+			issues = append(issues, Issue{f.Path, line, fmt.Sprintf(format, args...)})
+		}
+
+		forEachMessage(f.Messages, func(m *Message) {
+			cm, ok := currentMessages[qualify(f.Package, m.Name)]
+			if !ok {
+				issue(m.Line, "message %s was removed", m.Name)
+				return
+			}
+			for _, field := range m.Fields {
+				breakingField(cm.file, cm.message, field, func(format string, args ...interface{}) {
+					issues = append(issues, Issue{cm.file.Path, fieldLine(cm.message, field), fmt.Sprintf(format, args...)})
+				})
+			}
+		})
+
+		forEachEnum(f, func(e *Enum) {
+			ce, ok := currentEnums[qualify(f.Package, e.Name)]
+			if !ok {
+				issue(e.Line, "enum %s was removed", e.Name)
+				return
+			}
+			for _, value := range e.Values {
+				if ce.enum.valueByNumber(value.Number) == nil && !ce.enum.Reserved.HasNumber(value.Number) {
+					issues = append(issues, Issue{ce.file.Path, ce.enum.Line, fmt.Sprintf(
+						"value %s (%d) of enum %s was removed without reserving its number",
+						value.Name, value.Number, e.Name,
+					)})
+				}
+			}
+		})
+	}
+	sortIssues(issues)
+	return issues
+}
+
+// breakingField reports the breaking changes made to field of a previous version of m.
+func breakingField(f *File, m *Message, field *Field, issue func(format string, args ...interface{})) {
+	current := m.fieldByNumber(field.Number)
+	if current == nil {
+		if renamed := m.fieldByName(field.Name); renamed != nil {
+			issue("field %s of message %s was renumbered from %d to %d", field.Name, m.Name, field.Number, renamed.Number)
+			return
+		}
+		if !m.Reserved.HasNumber(field.Number) {
+			issue("field %s (%d) of message %s was removed without reserving its number", field.Name, field.Number, m.Name)
+		}
+		return
+	}
+	if typeName(current.Type, f.Package) != typeName(field.Type, f.Package) {
+		issue("field %s (%d) of message %s changed type from %s to %s", current.Name, field.Number, m.Name, field.Type, current.Type)
+	}
+	if current.Repeated != field.Repeated {
+		if current.Repeated {
+			issue("field %s (%d) of message %s became repeated", current.Name, field.Number, m.Name)
+		} else {
+			issue("field %s (%d) of message %s is no longer repeated", current.Name, field.Number, m.Name)
+		}
+	}
+}
+
+// fieldLine returns the line of the field of m with the number of field, or the line of m.
+func fieldLine(m *Message, field *Field) int {
+	if current := m.fieldByNumber(field.Number); current != nil {
+		return current.Line
+	}
+	return m.Line
+}
+
+func (m *Message) fieldByNumber(number int) *Field {
+	for _, f := range m.Fields {
+		if f.Number == number {
+			return f
+		}
+	}
+	return nil
+}
+
+func (m *Message) fieldByName(name string) *Field {
+	for _, f := range m.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (e *Enum) valueByNumber(number int) *EnumValue {
+	for _, v := range e.Values {
+		if v.Number == number {
+			return v
+		}
+	}
+	return nil
+}
+
+type fileMessage struct {
+	file    *File
+	message *Message
+}
+
+type fileEnum struct {
+	file *File
+	enum *Enum
+}
+
+// messagesByName indexes the messages of files by their fully qualified names.
+func messagesByName(files []*File) map[string]fileMessage {
+	messages := make(map[string]fileMessage)
+	for _, f := range files {
+		f := f
+		forEachMessage(f.Messages, func(m *Message) {
+			messages[qualify(f.Package, m.Name)] = fileMessage{f, m}
+		})
+	}
+	return messages
+}
+
+// enumsByName indexes the enums of files by their fully qualified names.
+func enumsByName(files []*File) map[string]fileEnum {
+	enums := make(map[string]fileEnum)
+	for _, f := range files {
+		f := f
+		forEachEnum(f, func(e *Enum) {
+			enums[qualify(f.Package, e.Name)] = fileEnum{f, e}
+		})
+	}
+	return enums
+}
+
+func forEachEnum(f *File, fn func(e *Enum)) {
+	for _, e := range f.Enums {
+		fn(e)
+	}
+	forEachMessage(f.Messages, func(m *Message) {
+		for _, e := range m.Enums {
+			fn(e)
+		}
+	})
+}
+
+// typeName normalizes a type name written in a file of pkg, types of the same package can be
+// written with or without their package.
+func typeName(name, pkg string) string {
+	name = strings.TrimPrefix(name, ".")
+	return strings.TrimPrefix(name, pkg+".")
+}
+
+func splitName(name string) []string {
+	return strings.Split(name, ".")
+}
+
+func lastSegment(name string) string {
+	segments := splitName(name)
+	return segments[len(segments)-1]
+}
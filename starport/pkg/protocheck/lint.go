@@ -0,0 +1,87 @@
+package protocheck
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Issue is a lint issue or a breaking change found in a proto file.
+type Issue struct {
+	// Path of the file relative to its proto path.
+	Path    string
+	Line    int
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s", i.Path, i.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", i.Path, i.Line, i.Message)
+}
+
+var (
+	packageSegmentRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	fieldNameRe      = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$|^[a-z][a-zA-Z0-9]*$`)
+	messageNameRe    = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+)
+
+// Lint lints the files for the conventions of the proto files of Cosmos apps: packages are
+// lower case and dot separated, files set a go_package, messages are PascalCase and
+// fields are lower_snake_case, or lowerCamelCase like the fields scaffolded by Starport.
+func Lint(files ...*File) []Issue {
+	var issues []Issue
+	for _, f := range files {
+		issue := func(line int, format string, args ...interface{}) {
+			issues = append(issues, Issue{f.Path, line, fmt.Sprintf(format, args...)})
+		}
+
+		if f.Package == "" {
+			issue(0, "package is not set")
+		} else if !isPackageName(f.Package) {
+			issue(f.PackageLine, "package %s should be lower case, e.g.: %s", f.Package, "cosmos.bank.v1beta1")
+		}
+		if f.GoPackage == "" {
+			issue(f.PackageLine, "go_package option is not set")
+		}
+
+		forEachMessage(f.Messages, func(m *Message) {
+			if name := lastSegment(m.Name); !messageNameRe.MatchString(name) {
+				issue(m.Line, "message %s should be PascalCase", m.Name)
+			}
+			for _, field := range m.Fields {
+				if !fieldNameRe.MatchString(field.Name) {
+					issue(field.Line, "field %s of message %s should be lower_snake_case or lowerCamelCase", field.Name, m.Name)
+				}
+			}
+		})
+	}
+	sortIssues(issues)
+	return issues
+}
+
+func isPackageName(name string) bool {
+	for _, segment := range splitName(name) {
+		if !packageSegmentRe.MatchString(segment) {
+			return false
+		}
+	}
+	return true
+}
+
+func forEachMessage(messages []*Message, fn func(m *Message)) {
+	for _, m := range messages {
+		fn(m)
+		forEachMessage(m.Messages, fn)
+	}
+}
+
+func sortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Path != issues[j].Path {
+			return issues[i].Path < issues[j].Path
+		}
+		return issues[i].Line < issues[j].Line
+	})
+}
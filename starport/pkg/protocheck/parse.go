@@ -0,0 +1,464 @@
+// Package protocheck lints proto files and detects the wire-breaking changes between two
+// versions of them.
+package protocheck
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// File is a parsed proto file.
+type File struct {
+	// Path of the file relative to its proto path.
+	Path string
+
+	Package     string
+	PackageLine int
+
+	// GoPackage is the go_package option of the file.
+	GoPackage string
+
+	Messages []*Message
+	Enums    []*Enum
+}
+
+// Message is a message of a proto file, nested messages are named after their parents, e.g.: Outer.Inner.
+type Message struct {
+	Name     string
+	Line     int
+	Fields   []*Field
+	Messages []*Message
+	Enums    []*Enum
+	Reserved Reserved
+}
+
+// Field is a field of a message.
+type Field struct {
+	Name     string
+	Number   int
+	Line     int
+	Repeated bool
+
+	// Type of the field as written in the file, e.g.: string, cosmos.base.v1beta1.Coin, map<string,uint64>.
+	Type string
+
+	// Oneof is the name of the oneof holding the field, if any.
+	Oneof string
+}
+
+// Enum is an enum of a proto file or a message.
+type Enum struct {
+	Name     string
+	Line     int
+	Values   []*EnumValue
+	Reserved Reserved
+}
+
+// EnumValue is a value of an enum.
+type EnumValue struct {
+	Name   string
+	Number int
+	Line   int
+}
+
+// Reserved holds the reserved numbers and names of a message or an enum.
+type Reserved struct {
+	Ranges [][2]int
+	Names  []string
+}
+
+// HasNumber checks if number is reserved.
+func (r Reserved) HasNumber(number int) bool {
+	for _, rg := range r.Ranges {
+		if number >= rg[0] && number <= rg[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// maxFieldNumber is the number reserved by the max keyword of ranges.
+const maxFieldNumber = 536870911
+
+type token struct {
+	text string
+	line int
+
+	// str is set for string literals, their text is unquoted.
+	str bool
+}
+
+// tokenize splits proto source into tokens, comments are skipped.
+func tokenize(src string) ([]token, error) {
+	var (
+		tokens []token
+		line   = 1
+	)
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end == -1 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			tokens = append(tokens, token{text: src[i+1 : j], line: line, str: true})
+			i = j + 1
+		case isIdentRune(rune(c)) || c == '.':
+			j := i
+			for j < len(src) && (isIdentRune(rune(src[j])) || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{text: src[i:j], line: line})
+			i = j
+		default:
+			tokens = append(tokens, token{text: string(c), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	line := 0
+	if p.pos < len(p.tokens) {
+		line = p.tokens[p.pos].line
+	} else if len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+	}
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) expect(text string) error {
+	if t := p.next(); t.text != text || t.str {
+		p.pos--
+		return p.errorf("expected %q, found %q", text, t.text)
+	}
+	return nil
+}
+
+// skipStatement skips tokens until the end of the statement, including blocks.
+func (p *parser) skipStatement() error {
+	depth := 0
+	for !p.done() {
+		t := p.next()
+		if t.str {
+			continue
+		}
+		switch t.text {
+		case "{", "[", "(", "<":
+			depth++
+		case "}", "]", ")", ">":
+			depth--
+			if depth == 0 && t.text == "}" && (p.peek().text != ";" || p.peek().str) {
+				return nil
+			}
+		case ";":
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+	return p.errorf("unexpected end of file")
+}
+
+// Parse parses the proto file at path with content.
+func Parse(path string, content []byte) (*File, error) {
+	tokens, err := tokenize(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	p := &parser{tokens: tokens}
+	f := &File{Path: path}
+	if err := p.parseFile(f); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return f, nil
+}
+
+func (p *parser) parseFile(f *File) error {
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case ";":
+		case "package":
+			f.Package = p.next().text
+			f.PackageLine = t.line
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "option":
+			name, value, err := p.parseOption()
+			if err != nil {
+				return err
+			}
+			if name == "go_package" {
+				f.GoPackage = value
+			}
+		case "message":
+			m, err := p.parseMessage("")
+			if err != nil {
+				return err
+			}
+			f.Messages = append(f.Messages, m)
+		case "enum":
+			e, err := p.parseEnum("")
+			if err != nil {
+				return err
+			}
+			f.Enums = append(f.Enums, e)
+		default:
+			// syntax, import, service, extend.
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseOption parses an option statement after the option keyword.
+func (p *parser) parseOption() (name, value string, err error) {
+	for !p.done() && p.peek().text != "=" {
+		name += p.next().text
+	}
+	if err := p.expect("="); err != nil {
+		return "", "", err
+	}
+	if t := p.peek(); t.str || t.text != "{" {
+		value = p.next().text
+		return name, value, p.expect(";")
+	}
+	return name, "", p.skipStatement()
+}
+
+func (p *parser) parseMessage(parent string) (*Message, error) {
+	nameToken := p.next()
+	m := &Message{Name: qualify(parent, nameToken.text), Line: nameToken.line}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	return m, p.parseMessageBody(m, "")
+}
+
+// parseMessageBody parses the body of a message or a oneof until its closing brace.
+func (p *parser) parseMessageBody(m *Message, oneof string) error {
+	for {
+		if p.done() {
+			return p.errorf("unexpected end of file in message %s", m.Name)
+		}
+		t := p.peek()
+		switch t.text {
+		case "}":
+			p.next()
+			return nil
+		case ";":
+			p.next()
+		case "option", "extensions", "extend":
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		case "reserved":
+			p.next()
+			if err := p.parseReserved(&m.Reserved); err != nil {
+				return err
+			}
+		case "message":
+			p.next()
+			nested, err := p.parseMessage(m.Name)
+			if err != nil {
+				return err
+			}
+			m.Messages = append(m.Messages, nested)
+		case "enum":
+			p.next()
+			e, err := p.parseEnum(m.Name)
+			if err != nil {
+				return err
+			}
+			m.Enums = append(m.Enums, e)
+		case "oneof":
+			p.next()
+			name := p.next().text
+			if err := p.expect("{"); err != nil {
+				return err
+			}
+			if err := p.parseMessageBody(m, name); err != nil {
+				return err
+			}
+		default:
+			field, err := p.parseField()
+			if err != nil {
+				return err
+			}
+			field.Oneof = oneof
+			m.Fields = append(m.Fields, field)
+		}
+	}
+}
+
+func (p *parser) parseField() (*Field, error) {
+	f := &Field{Line: p.peek().line}
+	switch p.peek().text {
+	case "repeated":
+		f.Repeated = true
+		p.next()
+	case "optional", "required":
+		p.next()
+	}
+	if p.peek().text == "map" && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].text == "<" {
+		p.next()
+		var typ strings.Builder
+		typ.WriteString("map")
+		for !p.done() {
+			t := p.next()
+			typ.WriteString(t.text)
+			if t.text == ">" {
+				break
+			}
+		}
+		f.Type = typ.String()
+	} else {
+		f.Type = p.next().text
+	}
+	f.Name = p.next().text
+	if err := p.expect("="); err != nil {
+		return nil, err
+	}
+	number, err := strconv.Atoi(p.next().text)
+	if err != nil {
+		return nil, p.errorf("invalid number of field %s", f.Name)
+	}
+	f.Number = number
+	return f, p.skipStatement()
+}
+
+func (p *parser) parseEnum(parent string) (*Enum, error) {
+	nameToken := p.next()
+	e := &Enum{Name: qualify(parent, nameToken.text), Line: nameToken.line}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for {
+		if p.done() {
+			return nil, p.errorf("unexpected end of file in enum %s", e.Name)
+		}
+		t := p.next()
+		switch t.text {
+		case "}":
+			return e, nil
+		case ";":
+		case "option":
+			if err := p.skipStatement(); err != nil {
+				return nil, err
+			}
+		case "reserved":
+			if err := p.parseReserved(&e.Reserved); err != nil {
+				return nil, err
+			}
+		default:
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			value := p.next().text
+			if value == "-" {
+				value += p.next().text
+			}
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, p.errorf("invalid number of enum value %s", t.text)
+			}
+			e.Values = append(e.Values, &EnumValue{Name: t.text, Number: number, Line: t.line})
+			if err := p.skipStatement(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// parseReserved parses a reserved statement after the reserved keyword,
+// e.g.: reserved 2, 15, 9 to 11; or reserved "foo", "bar";
+func (p *parser) parseReserved(r *Reserved) error {
+	for !p.done() {
+		t := p.next()
+		switch {
+		case t.text == ";" && !t.str:
+			return nil
+		case t.text == "," && !t.str:
+		case t.str:
+			r.Names = append(r.Names, t.text)
+		default:
+			from, err := strconv.Atoi(t.text)
+			if err != nil {
+				return p.errorf("invalid reserved number %s", t.text)
+			}
+			to := from
+			if p.peek().text == "to" {
+				p.next()
+				end := p.next().text
+				if end == "max" {
+					to = maxFieldNumber
+				} else if to, err = strconv.Atoi(end); err != nil {
+					return p.errorf("invalid reserved number %s", end)
+				}
+			}
+			r.Ranges = append(r.Ranges, [2]int{from, to})
+		}
+	}
+	return p.errorf("unexpected end of file")
+}
+
+func qualify(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
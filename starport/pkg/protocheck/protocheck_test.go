@@ -0,0 +1,172 @@
+package protocheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const blogProto = `syntax = "proto3";
+package foo.blog;
+
+/* the types
+   of the blog */
+option go_package = "github.com/foo/blog/x/blog/types";
+
+import "gogoproto/gogo.proto";
+import "cosmos/base/v1beta1/coin.proto";
+
+// Post is a post.
+message Post {
+  string creator = 1 [(gogoproto.moretags) = "yaml:\"creator\""];
+  uint64 id = 2;
+  repeated cosmos.base.v1beta1.Coin price = 3 [(gogoproto.nullable) = false];
+  map<string, uint64> votes = 4;
+  oneof content {
+    string text = 5;
+    bytes image = 6;
+  }
+  reserved 7, 9 to 11;
+  reserved "title";
+
+  message Meta {
+    Status status = 1;
+  }
+}
+
+enum Status {
+  option allow_alias = true;
+  DRAFT = 0;
+  PUBLISHED = 1 [deprecated = true];
+  LEGACY = -1;
+}
+
+service Query {
+  rpc Post(Post) returns (Post) {
+    option (google.api.http).get = "/foo/blog/post/{id}";
+  }
+}
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse("blog/post.proto", []byte(blogProto))
+	require.NoError(t, err)
+	require.Equal(t, "foo.blog", f.Package)
+	require.Equal(t, 2, f.PackageLine)
+	require.Equal(t, "github.com/foo/blog/x/blog/types", f.GoPackage)
+
+	require.Len(t, f.Messages, 1)
+	post := f.Messages[0]
+	require.Equal(t, "Post", post.Name)
+	require.Equal(t, 12, post.Line)
+	require.Equal(t, []*Field{
+		{Name: "creator", Number: 1, Line: 13, Type: "string"},
+		{Name: "id", Number: 2, Line: 14, Type: "uint64"},
+		{Name: "price", Number: 3, Line: 15, Type: "cosmos.base.v1beta1.Coin", Repeated: true},
+		{Name: "votes", Number: 4, Line: 16, Type: "map<string,uint64>"},
+		{Name: "text", Number: 5, Line: 18, Type: "string", Oneof: "content"},
+		{Name: "image", Number: 6, Line: 19, Type: "bytes", Oneof: "content"},
+	}, post.Fields)
+	require.Equal(t, Reserved{Ranges: [][2]int{{7, 7}, {9, 11}}, Names: []string{"title"}}, post.Reserved)
+	require.True(t, post.Reserved.HasNumber(10))
+	require.False(t, post.Reserved.HasNumber(8))
+
+	require.Len(t, post.Messages, 1)
+	require.Equal(t, "Post.Meta", post.Messages[0].Name)
+
+	require.Len(t, f.Enums, 1)
+	require.Equal(t, []*EnumValue{
+		{Name: "DRAFT", Number: 0, Line: 31},
+		{Name: "PUBLISHED", Number: 1, Line: 32},
+		{Name: "LEGACY", Number: -1, Line: 33},
+	}, f.Enums[0].Values)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse("a.proto", []byte("message Post {\n  string creator = one;\n}"))
+	require.EqualError(t, err, "a.proto: line 2: invalid number of field creator")
+
+	_, err = Parse("a.proto", []byte("message Post {\n  string creator = 1;\n"))
+	require.Error(t, err)
+}
+
+func TestLint(t *testing.T) {
+	f, err := Parse("blog/post.proto", []byte(`syntax = "proto3";
+package Foo.Blog;
+
+message post {
+  string Creator = 1;
+  uint64 post_iD = 2;
+  string created_at = 3;
+  string createdBy = 4;
+  string bad__name = 5;
+}
+`))
+	require.NoError(t, err)
+	require.Equal(t, []Issue{
+		{"blog/post.proto", 2, "package Foo.Blog should be lower case, e.g.: cosmos.bank.v1beta1"},
+		{"blog/post.proto", 2, "go_package option is not set"},
+		{"blog/post.proto", 4, "message post should be PascalCase"},
+		{"blog/post.proto", 5, "field Creator of message post should be lower_snake_case or lowerCamelCase"},
+		{"blog/post.proto", 6, "field post_iD of message post should be lower_snake_case or lowerCamelCase"},
+		{"blog/post.proto", 9, "field bad__name of message post should be lower_snake_case or lowerCamelCase"},
+	}, Lint(f))
+
+	f, err = Parse("blog/post.proto", []byte(blogProto))
+	require.NoError(t, err)
+	require.Empty(t, Lint(f))
+}
+
+func TestBreaking(t *testing.T) {
+	previous, err := Parse("blog/post.proto", []byte(`package foo.blog;
+message Post {
+  string creator = 1;
+  uint64 id = 2;
+  string title = 3;
+  string body = 4;
+  Status status = 5;
+  string tags = 6;
+  string removed = 7;
+}
+message Comment {
+  string body = 1;
+}
+enum Status {
+  DRAFT = 0;
+  PUBLISHED = 1;
+  ARCHIVED = 2;
+}
+`))
+	require.NoError(t, err)
+	current, err := Parse("blog/post.proto", []byte(`package foo.blog;
+message Post {
+  string creator = 1;
+  int64 id = 2;
+  string body = 8;
+  .foo.blog.Status status = 5;
+  repeated string tags = 6;
+  reserved 3;
+}
+enum Status {
+  DRAFT = 0;
+  PUBLISHED = 1;
+}
+`))
+	require.NoError(t, err)
+
+	require.Equal(t, []Issue{
+		{"blog/post.proto", 2, "field body of message Post was renumbered from 4 to 8"},
+		{"blog/post.proto", 2, "field removed (7) of message Post was removed without reserving its number"},
+		{"blog/post.proto", 4, "field id (2) of message Post changed type from uint64 to int64"},
+		{"blog/post.proto", 7, "field tags (6) of message Post became repeated"},
+		{"blog/post.proto", 10, "value ARCHIVED (2) of enum Status was removed without reserving its number"},
+		{"blog/post.proto", 11, "message Comment was removed"},
+	}, Breaking([]*File{previous}, []*File{current}))
+
+	require.Empty(t, Breaking([]*File{previous}, []*File{previous}))
+}
+
+func TestIssueString(t *testing.T) {
+	require.Equal(t, "a.proto:2: bad", Issue{"a.proto", 2, "bad"}.String())
+	require.Equal(t, "a.proto: bad", Issue{"a.proto", 0, "bad"}.String())
+}
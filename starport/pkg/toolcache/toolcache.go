@@ -0,0 +1,133 @@
+// Package toolcache builds Go tools once into a cache of versioned binaries. the tools are built
+// in their own module, so they don't add requirements to the module of an app, and builds work
+// offline once the tools are cached.
+package toolcache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/starport/starport/pkg/cmdrunner"
+	"github.com/tendermint/starport/starport/pkg/cmdrunner/step"
+)
+
+// Tool is a Go tool built from the main package of a module.
+type Tool struct {
+	// Path is the import path of the main package of the tool.
+	Path string
+
+	// Version is the version of the module of the tool.
+	Version string
+
+	// Replace holds the replace directives the tool is built with, e.g.:
+	// github.com/gogo/protobuf => github.com/regen-network/protobuf@v1.3.3-alpha.regen.1.
+	Replace map[string]string
+}
+
+// Name returns the name of the binary of the tool.
+func (t Tool) Name() string {
+	name := path.Base(t.Path)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+func (t Tool) String() string {
+	return t.Path + "@" + t.Version
+}
+
+// Cache is a cache of tools in a dir, a tool is built to <dir>/<name>@<version>/<name>.
+// the zero Cache has no dir, its tools are looked up in PATH and never built.
+type Cache struct {
+	path string
+}
+
+// New returns the cache of tools at path.
+func New(path string) Cache {
+	return Cache{path: path}
+}
+
+// BinaryPath returns the path of the binary of tool in the cache.
+func (c Cache) BinaryPath(tool Tool) string {
+	if c.path == "" {
+		return tool.Name()
+	}
+	return filepath.Join(c.path, fmt.Sprintf("%s@%s", path.Base(tool.Path), tool.Version), tool.Name())
+}
+
+// Has checks if tool is in the cache.
+func (c Cache) Has(tool Tool) bool {
+	if c.path == "" {
+		_, err := exec.LookPath(tool.Name())
+		return err == nil
+	}
+	_, err := os.Stat(c.BinaryPath(tool))
+	return err == nil
+}
+
+// Ensure builds the tools missing from the cache.
+func (c Cache) Ensure(ctx context.Context, tools ...Tool) error {
+	for _, tool := range tools {
+		if c.Has(tool) {
+			continue
+		}
+		if c.path == "" {
+			return fmt.Errorf("%s is not installed", tool.Name())
+		}
+		if err := c.build(ctx, tool); err != nil {
+			return errors.Wrapf(err, "cannot build %s", tool)
+		}
+	}
+	return nil
+}
+
+// build builds tool in a temporary module and moves its binary to the cache.
+func (c Cache) build(ctx context.Context, tool Tool) error {
+	modPath, err := ioutil.TempDir("", "starport-tool")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(modPath)
+
+	binaryPath := c.BinaryPath(tool)
+	if err := os.MkdirAll(filepath.Dir(binaryPath), 0755); err != nil {
+		return err
+	}
+	// the binary is moved to its path once built so the cache never holds a partial binary.
+	tmpBinaryPath := binaryPath + ".tmp"
+	defer os.Remove(tmpBinaryPath)
+
+	steps := step.Steps{
+		step.New(step.Exec("go", "mod", "init", "starport-tools")),
+	}
+	for old, replacement := range tool.Replace {
+		steps.Add(step.New(step.Exec("go", "mod", "edit", "-replace", fmt.Sprintf("%s=%s", old, replacement))))
+	}
+	steps.Add(
+		step.New(step.Exec("go", "get", tool.String())),
+		step.New(step.Exec("go", "build", "-o", tmpBinaryPath, tool.Path)),
+	)
+	for _, s := range steps {
+		s.Env = append(s.Env, "GO111MODULE=on", "GOFLAGS=-mod=mod")
+	}
+
+	errb := &bytes.Buffer{}
+	if err := cmdrunner.
+		New(
+			cmdrunner.DefaultStderr(errb),
+			cmdrunner.DefaultWorkdir(modPath),
+		).
+		Run(ctx, steps...); err != nil {
+		return errors.Wrap(err, errb.String())
+	}
+	return os.Rename(tmpBinaryPath, binaryPath)
+}
@@ -0,0 +1,42 @@
+package toolcache
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	var (
+		path = t.TempDir()
+		c    = New(path)
+		tool = Tool{Path: "github.com/foo/bar/cmd/protoc-gen-bar", Version: "v1.2.3"}
+	)
+	require.Equal(t, filepath.Join(path, "protoc-gen-bar@v1.2.3", tool.Name()), c.BinaryPath(tool))
+	require.False(t, c.Has(tool))
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(c.BinaryPath(tool)), 0755))
+	require.NoError(t, ioutil.WriteFile(c.BinaryPath(tool), nil, 0755))
+	require.True(t, c.Has(tool))
+
+	// cached tools are not built again, the tool above can't be built.
+	require.NoError(t, c.Ensure(context.Background(), tool))
+
+	// other versions are cached separately.
+	require.False(t, c.Has(Tool{Path: tool.Path, Version: "v1.2.4"}))
+}
+
+func TestCacheZero(t *testing.T) {
+	var (
+		c    Cache
+		tool = Tool{Path: "github.com/foo/bar/cmd/protoc-gen-bar-missing", Version: "v1.2.3"}
+	)
+	// tools of the zero cache are looked up in PATH.
+	require.Equal(t, tool.Name(), c.BinaryPath(tool))
+	require.False(t, c.Has(tool))
+	require.Error(t, c.Ensure(context.Background(), tool))
+}
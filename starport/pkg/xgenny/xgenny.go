@@ -0,0 +1,35 @@
+// Package xgenny provides helpers for the genny generators.
+package xgenny
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/gobuffalo/genny"
+)
+
+// Find finds the file at path relative to the root of the runner r.
+// unlike r.Disk.Find, the existing files are read from the root instead of the working
+// directory, so the generators can modify an app located anywhere.
+// the files are cached in the disk of r under path, the name r.File writes them with, so
+// a file modified by a generator is found with its modifications by the next ones.
+func Find(r *genny.Runner, path string) (genny.File, error) {
+	if filepath.IsAbs(path) || r.Root == "" {
+		return r.Disk.Find(path)
+	}
+
+	for _, f := range r.Disk.Files() {
+		if f.Name() == path {
+			return f, nil
+		}
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(r.Root, path))
+	if err != nil {
+		return genny.NewFile(path, bytes.NewReader(nil)), err
+	}
+	f := genny.NewFile(path, bytes.NewReader(content))
+	r.Disk.Add(f)
+	return f, nil
+}
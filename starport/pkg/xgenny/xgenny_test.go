@@ -0,0 +1,64 @@
+package xgenny
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gobuffalo/genny"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	root, err := ioutil.TempDir("", "xgenny")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "app"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "app/app.go"), []byte("package app"), 0644))
+
+	r := genny.DryRunner(context.Background())
+	r.Root = root
+
+	f, err := Find(r, "app/app.go")
+	require.NoError(t, err)
+	require.Equal(t, "app/app.go", f.Name())
+	require.Equal(t, "package app", f.String())
+
+	_, err = Find(r, "app/export.go")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestFindModified(t *testing.T) {
+	root, err := ioutil.TempDir("", "xgenny")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "app.go"), []byte("a"), 0644))
+
+	r := genny.WetRunner(context.Background())
+	r.Root = root
+
+	// each modification is made on top of the previous one in the same run.
+	for _, next := range []string{"b", "c"} {
+		next := next
+		g := genny.New()
+		g.RunFn(func(r *genny.Runner) error {
+			f, err := Find(r, "app.go")
+			if err != nil {
+				return err
+			}
+			return r.File(genny.NewFileS("app.go", f.String()+next))
+		})
+		require.NoError(t, r.With(g))
+	}
+	require.NoError(t, r.Run())
+
+	content, err := ioutil.ReadFile(filepath.Join(root, "app.go"))
+	require.NoError(t, err)
+	require.Equal(t, "abc", string(content))
+
+	f, err := Find(r, "app.go")
+	require.NoError(t, err)
+	require.Equal(t, "abc", f.String())
+}
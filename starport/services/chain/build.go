@@ -4,19 +4,33 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
 	starporterrors "github.com/tendermint/starport/starport/errors"
+	"github.com/tendermint/starport/starport/pkg/binarycache"
 	"github.com/tendermint/starport/starport/pkg/cmdrunner"
 	"github.com/tendermint/starport/starport/pkg/cmdrunner/step"
 	"github.com/tendermint/starport/starport/pkg/cosmosprotoc"
 	"github.com/tendermint/starport/starport/pkg/cosmosver"
-	"github.com/tendermint/starport/starport/pkg/goenv"
+	"github.com/tendermint/starport/starport/pkg/dirchange"
+	"github.com/tendermint/starport/starport/pkg/gomodule"
+	"github.com/tendermint/starport/starport/pkg/toolcache"
 	"github.com/tendermint/starport/starport/pkg/xos"
+	"github.com/tendermint/starport/starport/services"
+)
+
+const (
+	// protoCacheFile is the file caching the hashes of the proto files of the app.
+	protoCacheFile = "proto_cache.json"
+
+	// binaryCacheDir is the dir caching the binaries of the app by the sources they're built from.
+	binaryCacheDir = "binaries"
 )
 
 // Build builds an app.
@@ -29,7 +43,7 @@ func (c *Chain) Build(ctx context.Context) error {
 		return err
 	}
 
-	steps, err := c.buildSteps()
+	steps, err := c.buildSteps(true)
 	if err != nil {
 		return err
 	}
@@ -48,18 +62,19 @@ func (c *Chain) Build(ctx context.Context) error {
 	return nil
 }
 
-func (c *Chain) buildSteps() (steps step.Steps, err error) {
+// ldflags returns the linker flags setting the version info of the app binaries.
+func (c *Chain) ldflags() (string, error) {
 	chainID, err := c.ID()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	binary, err := c.Binary()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	ldflags := fmt.Sprintf(`-X github.com/cosmos/cosmos-sdk/version.Name=NewApp
+	return fmt.Sprintf(`-X github.com/cosmos/cosmos-sdk/version.Name=NewApp
 -X github.com/cosmos/cosmos-sdk/version.ServerName=%sd
 -X github.com/cosmos/cosmos-sdk/version.ClientName=%scli
 -X github.com/cosmos/cosmos-sdk/version.Version=%s
@@ -72,7 +87,200 @@ func (c *Chain) buildSteps() (steps step.Steps, err error) {
 		c.app.ImportPath,
 		binary,
 		chainID,
+	), nil
+}
+
+// buildBinaries builds the binaries of the app, or restores them from the binary cache when
+// they were already built from the same sources and build flags, e.g.: before switching branches.
+// the dependencies are tidied before the build when tidy is true.
+func (c *Chain) buildBinaries(ctx context.Context, tidy bool) error {
+	savePath, err := c.chainSavePath()
+	if err != nil {
+		return err
+	}
+	cache := binarycache.New(filepath.Join(savePath, binaryCacheDir))
+
+	// the version stamped into the binaries is the one of the sources being built.
+	if err := c.updateSourceVersion(); err != nil {
+		return err
+	}
+
+	key, err := c.binaryCacheKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	outputPath, err := c.outputPath()
+	if err != nil {
+		return err
+	}
+
+	restored, err := cache.Restore(key, outputPath)
+	if err != nil {
+		return err
+	}
+	if restored {
+		fmt.Fprintln(c.stdLog(logStarport).out, "🗃  Restored the app from a previous build of the same sources.")
+		return nil
+	}
+
+	steps, err := c.buildSteps(tidy)
+	if err != nil {
+		return err
+	}
+	if err := cmdrunner.
+		New(c.cmdOptions()...).
+		Run(ctx, steps...); err != nil {
+		return err
+	}
+
+	binaries, err := c.appBinaries()
+	if err != nil {
+		return err
+	}
+	var paths []string
+	for _, binary := range binaries {
+		paths = append(paths, filepath.Join(outputPath, binary.name))
+	}
+	return cache.Put(key, paths...)
+}
+
+// binaryCacheKey returns the key of the binaries built from the current sources, go.mod, go.sum,
+// vendored dependencies and build flags of the app, with the current Go toolchain.
+// the ldflags stamp the version of the app into the binaries, so the key includes its last
+// release tag and the commit of the tag. the commits made since the release don't change it.
+func (c *Chain) binaryCacheKey(ctx context.Context) (string, error) {
+	conf, err := c.Config()
+	if err != nil {
+		return "", err
+	}
+
+	sourceChecksum, err := dirchange.ChecksumFromPaths(c.app.Path, appBackendSourceWatchPaths)
+	if err != nil && !errors.Is(err, dirchange.ErrNoFile) {
+		return "", err
+	}
+
+	// the checksum covers the content of the sources only, their paths are added since moving
+	// a file to another package changes the binaries too.
+	sourcePaths, err := filePaths(c.app.Path, appBackendSourceWatchPaths)
+	if err != nil {
+		return "", err
+	}
+
+	parts := [][]byte{sourceChecksum, sourcePaths}
+	for _, name := range []string{"go.mod", "go.sum"} {
+		content, err := ioutil.ReadFile(filepath.Join(c.app.Path, name))
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		parts = append(parts, content)
+	}
+
+	// the dependencies are built from the vendor dir instead of the module cache, it can be
+	// edited without changing go.sum.
+	if gomodule.IsVendored(c.app.Path) {
+		vendor := []string{"vendor"}
+		vendorChecksum, err := dirchange.ChecksumFromPaths(c.app.Path, vendor)
+		if err != nil && !errors.Is(err, dirchange.ErrNoFile) {
+			return "", err
+		}
+		vendorPaths, err := filePaths(c.app.Path, vendor)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, vendorChecksum, vendorPaths)
+	}
+
+	toolchain, err := goToolchain(ctx, c.app.Path)
+	if err != nil {
+		return "", err
+	}
+	parts = append(parts, toolchain)
+
+	ldflags, err := c.ldflags()
+	if err != nil {
+		return "", err
+	}
+	cgo := "default"
+	if conf.Build.CGO != nil {
+		cgo = strconv.FormatBool(*conf.Build.CGO)
+	}
+	parts = append(parts,
+		[]byte(ldflags),
+		[]byte(strings.Join(conf.Build.LDFlags, " ")),
+		[]byte(strings.Join(conf.Build.Tags, ",")),
+		[]byte(cgo),
+		[]byte(gomodule.ModFlag(c.app.Path)),
 	)
+
+	binaries, err := c.appBinaries()
+	if err != nil {
+		return "", err
+	}
+	for _, binary := range binaries {
+		parts = append(parts, []byte(binary.name), []byte(binary.mainPath))
+	}
+
+	return binarycache.Key(parts...), nil
+}
+
+// filePaths lists the paths of the files under paths, relative to root.
+func filePaths(root string, paths []string) ([]byte, error) {
+	var list bytes.Buffer
+	for _, path := range paths {
+		err := filepath.Walk(filepath.Join(root, path), func(path string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil || info.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(root, path)
+			fmt.Fprintln(&list, rel)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return list.Bytes(), nil
+}
+
+// goToolchain returns the version and target platform of the go command building the app at
+// path. the go commands older than 1.16 don't have GOVERSION, their version is given by go version.
+func goToolchain(ctx context.Context, path string) ([]byte, error) {
+	run := func(args ...string) ([]byte, error) {
+		var out, errb bytes.Buffer
+		err := cmdrunner.
+			New(
+				cmdrunner.DefaultStdout(&out),
+				cmdrunner.DefaultStderr(&errb),
+				cmdrunner.DefaultWorkdir(path),
+			).
+			Run(ctx, step.New(step.Exec("go", args...)))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", err, errb.String())
+		}
+		return out.Bytes(), nil
+	}
+
+	env, err := run("env", "GOVERSION", "GOOS", "GOARCH")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(env, []byte("\n")) {
+		return env, nil
+	}
+	version, err := run("version")
+	if err != nil {
+		return nil, err
+	}
+	return append(version, env...), nil
+}
+
+// buildSteps returns the steps building the app, the dependencies are tidied and verified
+// first when tidy is true.
+func (c *Chain) buildSteps(tidy bool) (steps step.Steps, err error) {
 	var (
 		buildErr = &bytes.Buffer{}
 	)
@@ -84,35 +292,44 @@ func (c *Chain) buildSteps() (steps step.Steps, err error) {
 		return err
 	}
 
-	steps.Add(step.New(step.NewOptions().
-		Add(
-			step.Exec(
-				"go",
-				"mod",
-				"tidy",
-			),
-			step.PreExec(func() error {
-				fmt.Fprintln(c.stdLog(logStarport).out, "📦 Installing dependencies...")
-				return nil
-			}),
-			step.PostExec(captureBuildErr),
-		).
-		Add(c.stdSteps(logStarport)...).
-		Add(step.Stderr(buildErr))...,
-	))
+	conf, err := c.Config()
+	if err != nil {
+		return nil, err
+	}
 
-	steps.Add(step.New(step.NewOptions().
-		Add(
-			step.Exec(
-				"go",
-				"mod",
-				"verify",
-			),
-			step.PostExec(captureBuildErr),
-		).
-		Add(c.stdSteps(logBuild)...).
-		Add(step.Stderr(buildErr))...,
-	))
+	// tidy would update the modules of a vendored app without updating its vendor dir, and
+	// verify checks the module cache a vendored build doesn't use.
+	if tidy && !conf.Build.SkipTidy && !gomodule.IsVendored(c.app.Path) {
+		steps.Add(step.New(step.NewOptions().
+			Add(
+				step.Exec(
+					"go",
+					"mod",
+					"tidy",
+				),
+				step.PreExec(func() error {
+					fmt.Fprintln(c.stdLog(logStarport).out, "📦 Installing dependencies...")
+					return nil
+				}),
+				step.PostExec(captureBuildErr),
+			).
+			Add(c.stdSteps(logStarport)...).
+			Add(step.Stderr(buildErr))...,
+		))
+
+		steps.Add(step.New(step.NewOptions().
+			Add(
+				step.Exec(
+					"go",
+					"mod",
+					"verify",
+				),
+				step.PostExec(captureBuildErr),
+			).
+			Add(c.stdSteps(logBuild)...).
+			Add(step.Stderr(buildErr))...,
+		))
+	}
 
 	// install the app.
 	steps.Add(step.New(
@@ -122,17 +339,28 @@ func (c *Chain) buildSteps() (steps step.Steps, err error) {
 		}),
 	))
 
-	addInstallStep := func(binaryName, mainPath string) {
-		installPath := filepath.Join(goenv.GetGOBIN(), binaryName)
+	outputPath, err := c.outputPath()
+	if err != nil {
+		return nil, err
+	}
+
+	binaries, err := c.appBinaries()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, binary := range binaries {
+		buildOptions, err := c.goBuildOptions(binary.mainPath, filepath.Join(outputPath, binary.name))
+		if err != nil {
+			return nil, err
+		}
 
 		steps.Add(step.New(step.NewOptions().
+			Add(buildOptions...).
 			Add(
-				// ldflags somehow won't work if directly execute go binary.
-				// bash stays as a workaround for now.
-				step.Exec(
-					"bash", "-c", fmt.Sprintf("go build -mod readonly -o %s -ldflags '%s'", installPath, ldflags),
-				),
-				step.Workdir(mainPath),
+				step.PreExec(func() error {
+					return os.MkdirAll(outputPath, 0755)
+				}),
 				step.PostExec(captureBuildErr),
 			).
 			Add(c.stdSteps(logStarport)...).
@@ -140,15 +368,79 @@ func (c *Chain) buildSteps() (steps step.Steps, err error) {
 		))
 	}
 
-	cmdPath := filepath.Join(c.app.Path, "cmd")
+	return steps, nil
+}
 
-	addInstallStep(binary, filepath.Join(cmdPath, c.app.D()))
+// appBinary is a binary of the app with the path of its main package.
+type appBinary struct {
+	name     string
+	mainPath string
+}
+
+// appBinaries returns the binaries of the app with the paths of their main packages.
+func (c *Chain) appBinaries() ([]appBinary, error) {
+	binary, err := c.Binary()
+	if err != nil {
+		return nil, err
+	}
+
+	mainPath, err := c.mainPath()
+	if err != nil {
+		return nil, err
+	}
+
+	binaries := []appBinary{{binary, mainPath}}
 
 	if c.Version.Major().Is(cosmosver.Launchpad) {
-		addInstallStep(c.BinaryCLI(), filepath.Join(cmdPath, c.app.CLI()))
+		binaries = append(binaries, appBinary{c.BinaryCLI(), filepath.Join(c.app.Path, "cmd", c.app.CLI())})
 	}
 
-	return steps, nil
+	return binaries, nil
+}
+
+// goBuildOptions returns the options of a step building the main package at mainPath to out,
+// with the ldflags, tags and cgo setting of config.yml. env is set before the cgo setting.
+func (c *Chain) goBuildOptions(mainPath, out string, env ...string) (step.Options, error) {
+	conf, err := c.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	ldflags, err := c.ldflags()
+	if err != nil {
+		return nil, err
+	}
+	if len(conf.Build.LDFlags) > 0 {
+		ldflags += "\n" + strings.Join(conf.Build.LDFlags, " ")
+	}
+
+	command := []string{"go", "build", "-mod", gomodule.ModFlag(c.app.Path)}
+	if len(conf.Build.Tags) > 0 {
+		command = append(command, "-tags", shellQuote(strings.Join(conf.Build.Tags, ",")))
+	}
+	command = append(command, "-o", shellQuote(out), "-ldflags", shellQuote(ldflags))
+
+	if conf.Build.CGO != nil {
+		cgo := "0"
+		if *conf.Build.CGO {
+			cgo = "1"
+		}
+		env = append(env, "CGO_ENABLED="+cgo)
+	}
+
+	return step.NewOptions().
+		Add(
+			// ldflags somehow won't work if directly execute go binary.
+			// bash stays as a workaround for now.
+			step.Exec("bash", "-c", strings.Join(command, " ")),
+			step.Env(env...),
+			step.Workdir(mainPath),
+		), nil
+}
+
+// shellQuote quotes s to be passed as a single argument to bash.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 func (c *Chain) buildProto(ctx context.Context) error {
@@ -162,13 +454,24 @@ func (c *Chain) buildProto(ctx context.Context) error {
 		return nil
 	}
 
-	if err := cosmosprotoc.InstallDependencies(context.Background(), c.app.Path); err != nil {
+	backend, err := cosmosprotoc.NewBackend(conf.Build.Proto.Backend, toolcache.New(services.ToolsDir))
+	if err != nil {
+		return err
+	}
+
+	if err := backend.InstallDependencies(context.Background()); err != nil {
 		if err == cosmosprotoc.ErrProtocNotInstalled {
 			return starporterrors.ErrStarportRequiresProtoc
 		}
 		return err
 	}
 
+	// the code of the proto files unchanged since the previous build is not generated again.
+	savePath, err := c.chainSavePath()
+	if err != nil {
+		return err
+	}
+
 	fmt.Fprintln(c.stdLog(logStarport).out, "🛠️  Building proto...")
 
 	err = cosmosprotoc.Generate(
@@ -177,6 +480,8 @@ func (c *Chain) buildProto(ctx context.Context) error {
 		c.app.ImportPath,
 		filepath.Join(c.app.Path, conf.Build.Proto.Path),
 		xos.PrefixPathToList(conf.Build.Proto.ThirdPartyPaths, c.app.Path),
+		cosmosprotoc.WithBackend(backend),
+		cosmosprotoc.WithCache(filepath.Join(savePath, protoCacheFile)),
 	)
 
 	if err != nil {
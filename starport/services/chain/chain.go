@@ -8,14 +8,18 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/gookit/color"
 	conf "github.com/tendermint/starport/starport/chainconf"
 	secretconf "github.com/tendermint/starport/starport/chainconf/secret"
 	"github.com/tendermint/starport/starport/pkg/chaincmd"
 	chaincmdrunner "github.com/tendermint/starport/starport/pkg/chaincmd/runner"
 	"github.com/tendermint/starport/starport/pkg/cosmosver"
+	"github.com/tendermint/starport/starport/pkg/goenv"
+	"golang.org/x/mod/semver"
 )
 
 var (
@@ -33,6 +37,9 @@ var (
 
 	vuePath = "vue"
 
+	// frontendExts holds the extensions of frontend files, they never reload the backend.
+	frontendExts = []string{".vue", ".js", ".jsx", ".ts", ".tsx", ".css", ".scss", ".html"}
+
 	errorColor = color.Red.Render
 	infoColor  = color.Yellow.Render
 )
@@ -66,6 +73,10 @@ type Chain struct {
 	serveRefresher chan struct{}
 	served         bool
 	stdout, stderr io.Writer
+
+	// contracts holds the wasm contracts deployed to the served chain.
+	contracts   []DeployedContract
+	contractsMu sync.Mutex
 }
 
 // chainOptions holds user given options that overwrites chain's defaults.
@@ -146,8 +157,7 @@ func New(ctx context.Context, path string, options ...Option) (*Chain, error) {
 		c.stderr = os.Stderr
 	}
 
-	c.sourceVersion, err = c.appVersion()
-	if err != nil && err != git.ErrRepositoryNotExists {
+	if err := c.updateSourceVersion(); err != nil {
 		return nil, err
 	}
 
@@ -162,6 +172,20 @@ func New(ctx context.Context, path string, options ...Option) (*Chain, error) {
 	return c, nil
 }
 
+// updateSourceVersion sets the version of the app from its git tags, the tags can change while
+// the app is served, e.g.: after switching branches. the version is empty when the app isn't a
+// git repository.
+func (c *Chain) updateSourceVersion() error {
+	v, err := c.appVersion()
+	if err != nil && err != git.ErrRepositoryNotExists {
+		return err
+	}
+	c.sourceVersion = v
+	return nil
+}
+
+// appVersion returns the highest semantic version the app is tagged with, along with the commit
+// of the tag. the tags that aren't semantic versions are ignored.
 func (c *Chain) appVersion() (v version, err error) {
 	repo, err := git.PlainOpen(c.app.Path)
 	if err != nil {
@@ -171,12 +195,37 @@ func (c *Chain) appVersion() (v version, err error) {
 	if err != nil {
 		return version{}, err
 	}
-	ref, err := iter.Next()
-	if err != nil {
-		return version{}, nil
+	var (
+		latest  *plumbing.Reference
+		highest string
+	)
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		// tags are compared as semantic versions, with or without their v prefix
+		tag := "v" + strings.TrimPrefix(ref.Name().Short(), "v")
+		if semver.IsValid(tag) && (latest == nil || semver.Compare(tag, highest) > 0) {
+			latest, highest = ref, tag
+		}
+		return nil
+	})
+	if err != nil || latest == nil {
+		return version{}, err
 	}
-	v.tag = strings.TrimPrefix(ref.Name().Short(), "v")
-	v.hash = ref.Hash().String()
+
+	// tags are either lightweight and point to a commit, or annotated and point to a tag object.
+	hash := latest.Hash()
+	tag, err := repo.TagObject(hash)
+	switch {
+	case err == nil:
+		commit, err := tag.Commit()
+		if err != nil {
+			return version{}, err
+		}
+		hash = commit.Hash
+	case err != plumbing.ErrObjectNotFound:
+		return version{}, err
+	}
+	v.tag = strings.TrimPrefix(highest, "v")
+	v.hash = hash.String()
 	return v, nil
 }
 
@@ -265,6 +314,50 @@ func (c *Chain) BinaryCLI() string {
 	return c.app.CLI()
 }
 
+// BinaryPath returns the path where the app's default (appd) binary is installed.
+func (c *Chain) BinaryPath() (string, error) {
+	binary, err := c.Binary()
+	if err != nil {
+		return "", err
+	}
+	outputPath, err := c.outputPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(outputPath, binary), nil
+}
+
+// outputPath returns the dir where the binaries of the app are installed.
+func (c *Chain) outputPath() (string, error) {
+	conf, err := c.Config()
+	if err != nil {
+		return "", err
+	}
+	if conf.Build.Output == "" {
+		return goenv.GetGOBIN(), nil
+	}
+	output := os.ExpandEnv(conf.Build.Output)
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(c.app.Path, output)
+	}
+	return output, nil
+}
+
+// mainPath returns the path of the main package of the app's default (appd) binary.
+func (c *Chain) mainPath() (string, error) {
+	conf, err := c.Config()
+	if err != nil {
+		return "", err
+	}
+	if conf.Build.Main == "" {
+		return filepath.Join(c.app.Path, "cmd", c.app.D()), nil
+	}
+	if filepath.IsAbs(conf.Build.Main) {
+		return conf.Build.Main, nil
+	}
+	return filepath.Join(c.app.Path, conf.Build.Main), nil
+}
+
 // Binaries returns the list of binaries available for the chain.
 func (c *Chain) Binaries() ([]string, error) {
 	binary, err := c.Binary()
@@ -372,17 +465,22 @@ func (c *Chain) ConfigTOMLPath() (string, error) {
 
 // Commands returns the runner execute commands on the chain's binary
 func (c *Chain) Commands(ctx context.Context) (chaincmdrunner.Runner, error) {
-	id, err := c.ID()
+	home, err := c.Home()
 	if err != nil {
 		return chaincmdrunner.Runner{}, err
 	}
+	return c.commandsAt(ctx, home, c.genPrefix(logAppd))
+}
 
-	home, err := c.Home()
+// commandsAt returns the runner executing commands on the chain's binary with the node at home,
+// the logs of the daemon are prefixed by logPrefix.
+func (c *Chain) commandsAt(ctx context.Context, home, logPrefix string) (chaincmdrunner.Runner, error) {
+	id, err := c.ID()
 	if err != nil {
 		return chaincmdrunner.Runner{}, err
 	}
 
-	binary, err := c.Binary()
+	binaryPath, err := c.BinaryPath()
 	if err != nil {
 		return chaincmdrunner.Runner{}, err
 	}
@@ -404,8 +502,13 @@ func (c *Chain) Commands(ctx context.Context) (chaincmdrunner.Runner, error) {
 			return chaincmdrunner.Runner{}, err
 		}
 
+		outputPath, err := c.outputPath()
+		if err != nil {
+			return chaincmdrunner.Runner{}, err
+		}
+
 		ccoptions = append(ccoptions,
-			chaincmd.WithLaunchpadCLI(c.BinaryCLI()),
+			chaincmd.WithLaunchpadCLI(filepath.Join(outputPath, c.BinaryCLI())),
 			chaincmd.WithLaunchpadCLIHome(cliHome),
 		)
 	}
@@ -427,14 +530,14 @@ func (c *Chain) Commands(ctx context.Context) (chaincmdrunner.Runner, error) {
 		}
 	}
 
-	cc := chaincmd.New(binary, ccoptions...)
+	cc := chaincmd.New(binaryPath, ccoptions...)
 
 	ccroptions := []chaincmdrunner.Option{}
 	if c.logLevel == LogVerbose {
 		ccroptions = append(ccroptions,
 			chaincmdrunner.Stdout(os.Stdout),
 			chaincmdrunner.Stderr(os.Stderr),
-			chaincmdrunner.DaemonLogPrefix(c.genPrefix(logAppd)),
+			chaincmdrunner.DaemonLogPrefix(logPrefix),
 			chaincmdrunner.CLILogPrefix(c.genPrefix(logAppcli)),
 		)
 	}
@@ -0,0 +1,113 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppVersion(t *testing.T) {
+	path := t.TempDir()
+	repo, err := git.PlainInit(path, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	signature := &object.Signature{Name: "mars", Email: "mars@example.com", When: time.Now()}
+
+	commit := func(content string) plumbing.Hash {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(path, "README.md"), []byte(content), 0644))
+		_, err := wt.Add("README.md")
+		require.NoError(t, err)
+		hash, err := wt.Commit(content, &git.CommitOptions{Author: signature})
+		require.NoError(t, err)
+		return hash
+	}
+
+	c := &Chain{app: App{Path: path}}
+	v, err := c.appVersion()
+	require.NoError(t, err)
+	require.Equal(t, version{}, v)
+
+	// v0.10.0 is sorted before v0.2.0 by name.
+	first := commit("first")
+	for i := 1; i <= 9; i++ {
+		_, err := repo.CreateTag(fmt.Sprintf("v0.%d.0", i), first, nil)
+		require.NoError(t, err)
+	}
+	second := commit("second")
+	_, err = repo.CreateTag("v0.10.0", second, &git.CreateTagOptions{Tagger: signature, Message: "v0.10.0"})
+	require.NoError(t, err)
+	_, err = repo.CreateTag("latest", commit("third"), nil)
+	require.NoError(t, err)
+
+	v, err = c.appVersion()
+	require.NoError(t, err)
+	require.Equal(t, version{tag: "0.10.0", hash: second.String()}, v)
+}
+
+func TestBuildImageCGO(t *testing.T) {
+	path := t.TempDir()
+	config := `
+accounts:
+  - name: alice
+    coins: ["1token"]
+validator:
+  name: alice
+  staked: "1token"
+build:
+  cgo: true
+`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, "config.yml"), []byte(config), 0644))
+
+	c := &Chain{app: App{Path: path}}
+	err := c.BuildImage(context.Background(), "mars:latest", filepath.Join(path, "mars.tar"), "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "build.cgo")
+}
+
+func TestBinaryCacheKeyVersion(t *testing.T) {
+	path := t.TempDir()
+	repo, err := git.PlainInit(path, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	signature := &object.Signature{Name: "mars", Email: "mars@example.com", When: time.Now()}
+
+	commit := func(content string) plumbing.Hash {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(path, "README.md"), []byte(content), 0644))
+		_, err := wt.Add("README.md")
+		require.NoError(t, err)
+		hash, err := wt.Commit(content, &git.CommitOptions{Author: signature})
+		require.NoError(t, err)
+		return hash
+	}
+
+	c := &Chain{app: App{Name: "mars", Path: path, ImportPath: "github.com/foo/mars"}}
+	key := func() string {
+		require.NoError(t, c.updateSourceVersion())
+		key, err := c.binaryCacheKey(context.Background())
+		require.NoError(t, err)
+		return key
+	}
+
+	_, err = repo.CreateTag("v0.1.0", commit("first"), nil)
+	require.NoError(t, err)
+	released := key()
+
+	// the commits made since the release share the key of the release.
+	commit("second")
+	require.Equal(t, released, key())
+
+	// a new release is stamped into the binaries.
+	_, err = repo.CreateTag("v0.2.0", commit("third"), nil)
+	require.NoError(t, err)
+	require.NotEqual(t, released, key())
+}
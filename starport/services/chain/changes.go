@@ -0,0 +1,216 @@
+package chain
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	secretconf "github.com/tendermint/starport/starport/chainconf/secret"
+	"github.com/tendermint/starport/starport/pkg/dirchange"
+	"github.com/tendermint/starport/starport/pkg/gomodule"
+)
+
+const (
+	// protoChecksum is the file containing the checksum to detect proto files modification
+	protoChecksum = "proto_checksum.txt"
+
+	// depsChecksum is the file containing the checksum to detect imports and go.mod modification
+	depsChecksum = "deps_checksum.txt"
+
+	// nodeConfigChecksum is the file containing the checksum to detect init.app and init.config modification
+	nodeConfigChecksum = "node_config_checksum.txt"
+)
+
+// appChanges classifies the changes of the app since it was last served by what they require.
+type appChanges struct {
+	// proto is true when the proto files changed, their code is generated again.
+	proto bool
+
+	// source is true when the Go sources changed, the app is built again.
+	source bool
+
+	// deps is true when the imports of the Go sources or go.mod changed, go mod tidy runs again.
+	deps bool
+
+	// config is true when config.yml or secret.yml changed other than init.app and init.config,
+	// the state is reset.
+	config bool
+
+	// nodeConfig is true when init.app or init.config changed, the TOML configs of the nodes
+	// are updated.
+	nodeConfig bool
+}
+
+// any checks if the app changed in any way requiring a reload.
+func (a appChanges) any() bool {
+	return a.proto || a.source || a.deps || a.config || a.nodeConfig
+}
+
+// isFrontendFile checks if path is a file of a frontend, frontend files never reload the backend.
+func isFrontendFile(path string) bool {
+	for _, ext := range frontendExts {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// checksumFuncs returns the funcs computing the checksums changes are classified by, by the
+// names of their files.
+func (c *Chain) checksumFuncs() map[string]func() ([]byte, error) {
+	return map[string]func() ([]byte, error){
+		protoChecksum:      c.protoSum,
+		sourceChecksum:     c.sourceSum,
+		depsChecksum:       c.depsSum,
+		configChecksum:     c.configSum,
+		nodeConfigChecksum: c.nodeConfigSum,
+	}
+}
+
+// detectChanges detects the changes of the app since the checksums were last saved.
+func (c *Chain) detectChanges() (changes appChanges, err error) {
+	saveDir, err := c.chainSavePath()
+	if err != nil {
+		return appChanges{}, err
+	}
+
+	funcs := c.checksumFuncs()
+	for name, changed := range map[string]*bool{
+		protoChecksum:      &changes.proto,
+		sourceChecksum:     &changes.source,
+		depsChecksum:       &changes.deps,
+		configChecksum:     &changes.config,
+		nodeConfigChecksum: &changes.nodeConfig,
+	} {
+		if *changed, err = c.hasChecksumChanged(saveDir, name, funcs[name]); err != nil {
+			return appChanges{}, err
+		}
+	}
+	return changes, nil
+}
+
+// hasDepsChanged checks if the imports or go.mod changed since the checksums were last saved,
+// e.g.: after generating the code of the proto files.
+func (c *Chain) hasDepsChanged() (bool, error) {
+	saveDir, err := c.chainSavePath()
+	if err != nil {
+		return false, err
+	}
+	return c.hasChecksumChanged(saveDir, depsChecksum, c.depsSum)
+}
+
+func (c *Chain) hasChecksumChanged(saveDir, name string, checksum func() ([]byte, error)) (bool, error) {
+	sum, err := checksum()
+	if err != nil {
+		return false, err
+	}
+	return dirchange.HasChecksumChanged(sum, saveDir, name)
+}
+
+// saveChecksums saves the checksums of the app changes are detected from.
+func (c *Chain) saveChecksums() error {
+	saveDir, err := c.chainSavePath()
+	if err != nil {
+		return err
+	}
+	for name, checksum := range c.checksumFuncs() {
+		sum, err := checksum()
+		if err != nil {
+			return err
+		}
+		if err := dirchange.SaveChecksum(sum, saveDir, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// protoSum returns the checksum of the proto files of the app and its third party proto files.
+func (c *Chain) protoSum() ([]byte, error) {
+	conf, err := c.Config()
+	if err != nil {
+		return nil, err
+	}
+	paths := append([]string{conf.Build.Proto.Path}, conf.Build.Proto.ThirdPartyPaths...)
+	return checksumFromPaths(c.app.Path, paths, func(path string) bool {
+		return filepath.Ext(path) == ".proto"
+	})
+}
+
+// sourceSum returns the checksum of the backend sources of the app other than its proto files.
+func (c *Chain) sourceSum() ([]byte, error) {
+	return checksumFromPaths(c.app.Path, appBackendSourceWatchPaths, func(path string) bool {
+		return filepath.Ext(path) != ".proto" && !isFrontendFile(path)
+	})
+}
+
+// depsSum returns the checksum of the imports of the Go sources and go.mod of the app.
+func (c *Chain) depsSum() ([]byte, error) {
+	var paths []string
+	for _, path := range appBackendSourceWatchPaths {
+		paths = append(paths, filepath.Join(c.app.Path, path))
+	}
+	imports, err := gomodule.Imports(paths...)
+	if err != nil {
+		return nil, err
+	}
+	gomod, err := ioutil.ReadFile(filepath.Join(c.app.Path, "go.mod"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	hash := md5.New()
+	hash.Write([]byte(strings.Join(imports, "\n")))
+	hash.Write(gomod)
+	return hash.Sum(nil), nil
+}
+
+// configSum returns the checksum of config.yml other than init.app and init.config, and of secret.yml.
+func (c *Chain) configSum() ([]byte, error) {
+	conf, err := c.Config()
+	if err != nil {
+		return nil, err
+	}
+	conf.Init.App = nil
+	conf.Init.Config = nil
+	content, err := json.Marshal(conf)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := ioutil.ReadFile(filepath.Join(c.app.Path, secretconf.SecretFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	hash := md5.New()
+	hash.Write(content)
+	hash.Write(secret)
+	return hash.Sum(nil), nil
+}
+
+// nodeConfigSum returns the checksum of init.app and init.config of config.yml.
+func (c *Chain) nodeConfigSum() ([]byte, error) {
+	conf, err := c.Config()
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal([]map[string]interface{}{conf.Init.App, conf.Init.Config})
+	if err != nil {
+		return nil, err
+	}
+	sum := md5.Sum(content)
+	return sum[:], nil
+}
+
+// checksumFromPaths returns the checksum of the files under paths matching include, the checksum
+// is empty when there is no such file.
+func checksumFromPaths(workdir string, paths []string, include func(path string) bool) ([]byte, error) {
+	sum, err := dirchange.ChecksumFromPathsFunc(workdir, paths, include)
+	if errors.Is(err, dirchange.ErrNoFile) {
+		return []byte{}, nil
+	}
+	return sum, err
+}
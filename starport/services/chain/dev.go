@@ -27,6 +27,9 @@ type statusResponse struct {
 	Env    env           `json:"env"`
 	Status serviceStatus `json:"status"`
 	Addrs  serviceAddrs  `json:"addrs"`
+
+	// Contracts holds the wasm contracts deployed from config.yml.
+	Contracts []DeployedContract `json:"contracts"`
 }
 
 // serviceStatus holds the availibity status of http services.
@@ -56,6 +59,9 @@ type development struct {
 	app  App
 	conf Config
 	uifs http.FileSystem
+
+	// contracts returns the deployed wasm contracts.
+	contracts func() []DeployedContract
 }
 
 // Config used to configure development handler.
@@ -67,15 +73,16 @@ type Config struct {
 }
 
 // newDevHandler creates a new development server handler for app by given conf.
-func newDevHandler(app App, conf Config, grpcwebHandler http.Handler) (http.Handler, error) {
+func newDevHandler(app App, conf Config, grpcwebHandler http.Handler, contracts func() []DeployedContract) (http.Handler, error) {
 	uifs, err := fs.New()
 	if err != nil {
 		return nil, err
 	}
 	dev := &development{
-		app:  app,
-		conf: conf,
-		uifs: uifs,
+		app:       app,
+		conf:      conf,
+		uifs:      uifs,
+		contracts: contracts,
 	}
 
 	cors := cors.Default().Handler
@@ -131,6 +138,7 @@ func (d *development) statusHandler() http.Handler {
 				AppBackend:      d.conf.AppBackendAddr,
 				AppFrontend:     d.conf.AppFrontendAddr,
 			},
+			Contracts: d.contracts(),
 		}
 		xhttp.ResponseJSON(w, http.StatusOK, resp)
 	})
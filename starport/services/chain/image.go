@@ -0,0 +1,158 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/tendermint/starport/starport/pkg/cosmosver"
+	"github.com/tendermint/starport/starport/pkg/ociimage"
+	"github.com/tendermint/starport/starport/pkg/xurl"
+)
+
+// imageBinPath is the dir of the binaries of the app in its image.
+const imageBinPath = "/usr/local/bin"
+
+// BuildImage builds an OCI image tarball of the app tagged as ref at out, without a container
+// engine. the binaries of the app are cross-compiled for target in the GOOS:GOARCH format,
+// linux:amd64 by default, and added on top of a distroless-style base layer.
+// the image runs the node as the nonroot user, with its home in the home dir of the user.
+func (c *Chain) BuildImage(ctx context.Context, ref, out, target string) error {
+	if target == "" {
+		target = "linux:amd64"
+	}
+	targets, err := parseReleaseTargets([]string{target})
+	if err != nil {
+		return err
+	}
+	if targets[0].goos != "linux" {
+		return fmt.Errorf("invalid image target %s, images can only be built for linux", target)
+	}
+	if _, _, err := ociimage.ParseReference(ref); err != nil {
+		return err
+	}
+
+	conf, err := c.Config()
+	if err != nil {
+		return err
+	}
+	// the binaries are statically linked for the base layer, which has no C library.
+	if conf.Build.CGO != nil && *conf.Build.CGO {
+		return errors.New("images can't be built with build.cgo enabled in config.yml, their base layer has no C library for the binaries to link against")
+	}
+
+	if err := c.setup(ctx); err != nil {
+		return err
+	}
+
+	if err := c.buildProto(ctx); err != nil {
+		return err
+	}
+
+	outPath, err := ioutil.TempDir("", "starport-image")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(outPath)
+
+	fmt.Fprintf(c.stdLog(logStarport).out, "🛠️  Building the app for %s/%s...\n", targets[0].goos, targets[0].goarch)
+
+	binaryPaths, err := c.buildReleaseBinaries(ctx, targets[0], outPath)
+	if err != nil {
+		return err
+	}
+
+	// the home of the node in the image has the same name as its default home.
+	defaultHome, err := c.DefaultHome()
+	if err != nil {
+		return err
+	}
+	home := path.Join(ociimage.NonrootHome, filepath.Base(defaultHome))
+
+	appLayer := ociimage.Layer{
+		{Path: home, Mode: 0700, UID: ociimage.NonrootUID, GID: ociimage.NonrootGID, Dir: true},
+	}
+	for _, binaryPath := range binaryPaths {
+		appLayer = append(appLayer, ociimage.File{
+			Path:       path.Join(imageBinPath, filepath.Base(binaryPath)),
+			Mode:       0755,
+			SourcePath: binaryPath,
+		})
+	}
+
+	baseLayer, err := ociimage.BaseLayer()
+	if err != nil {
+		return err
+	}
+
+	binary, err := c.Binary()
+	if err != nil {
+		return err
+	}
+
+	// the node listens on the addresses of config.yml, so its servers can be reached from
+	// outside of the container.
+	cmd := []string{
+		"start",
+		"--home", home,
+		"--rpc.laddr", xurl.TCP(conf.Servers.RPCAddr),
+		"--p2p.laddr", xurl.TCP(conf.Servers.P2PAddr),
+	}
+	addresses := []string{conf.Servers.RPCAddr, conf.Servers.P2PAddr, conf.Servers.APIAddr}
+	if c.Version.Major().Is(cosmosver.Stargate) {
+		cmd = append(cmd, "--grpc.address", conf.Servers.GRPCAddr)
+		addresses = append(addresses, conf.Servers.GRPCAddr)
+	}
+
+	var ports []string
+	for _, address := range addresses {
+		port, err := addressPort(address)
+		if err != nil {
+			return err
+		}
+		ports = append(ports, port+"/tcp")
+	}
+
+	labels := map[string]string{
+		"org.opencontainers.image.title": c.app.Name,
+	}
+	if c.sourceVersion.tag != "" {
+		labels["org.opencontainers.image.version"] = c.sourceVersion.tag
+	}
+	if c.sourceVersion.hash != "" {
+		labels["org.opencontainers.image.revision"] = c.sourceVersion.hash
+	}
+
+	config := ociimage.Config{
+		OS:           targets[0].goos,
+		Architecture: targets[0].goarch,
+		User:         fmt.Sprintf("%d:%d", ociimage.NonrootUID, ociimage.NonrootGID),
+		Env:          []string{"HOME=" + ociimage.NonrootHome},
+		Entrypoint:   []string{path.Join(imageBinPath, binary)},
+		Cmd:          cmd,
+		WorkingDir:   ociimage.NonrootHome,
+		ExposedPorts: ports,
+		Volumes:      []string{home},
+		Labels:       labels,
+	}
+
+	file, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := ociimage.Write(file, ref, config, baseLayer, appLayer); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stdLog(logStarport).out, "🗃  Image %s created at: %s\n", ref, infoColor(out))
+	return nil
+}
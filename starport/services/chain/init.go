@@ -13,21 +13,25 @@ import (
 	secretconf "github.com/tendermint/starport/starport/chainconf/secret"
 
 	"github.com/imdario/mergo"
+	"github.com/otiai10/copy"
 	"github.com/tendermint/starport/starport/pkg/confile"
 	"github.com/tendermint/starport/starport/pkg/xos"
 )
 
 const (
 	moniker = "mynode"
+
+	// defaultConfigDir is the dir of the home of a node keeping the configs created by its init,
+	// the configs are re-created from them before init.app and init.config of config.yml are
+	// applied, so the keys removed from config.yml get their default values back.
+	defaultConfigDir = "config/default"
 )
 
+// nodeConfigFiles are the TOML configs of a node changed by init.app and init.config.
+var nodeConfigFiles = []string{"app.toml", "config.toml"}
+
 // Init initializes chain.
 func (c *Chain) Init(ctx context.Context) error {
-	chainID, err := c.ID()
-	if err != nil {
-		return err
-	}
-
 	conf, err := c.Config()
 	if err != nil {
 		return err
@@ -54,10 +58,24 @@ func (c *Chain) Init(ctx context.Context) error {
 		return err
 	}
 
+	return c.initNode(ctx, commands, home, moniker, conf)
+}
+
+// initNode initializes the node at home with moniker and overwrites its configs
+// with the ones of config.yml.
+func (c *Chain) initNode(ctx context.Context, commands chaincmdrunner.Runner, home, moniker string, conf conf.Config) error {
+	chainID, err := c.ID()
+	if err != nil {
+		return err
+	}
+
 	// init node.
 	if err := commands.Init(ctx, moniker); err != nil {
 		return err
 	}
+	if err := saveDefaultConfigs(home); err != nil {
+		return err
+	}
 
 	// overwrite configuration changes from Starport's config.yml to
 	// over app's sdk configs.
@@ -68,45 +86,83 @@ func (c *Chain) Init(ctx context.Context) error {
 	}
 
 	// Initilize app config
-	genesisPath, err := c.GenesisPath()
-	if err != nil {
+	genesis := configChanges{confile.DefaultJSONEncodingCreator, filepath.Join(home, "config/genesis.json"), conf.Genesis}
+	if err := genesis.apply(); err != nil {
 		return err
 	}
-	appTOMLPath, err := c.AppTOMLPath()
-	if err != nil {
+
+	return c.configureNode(home, conf)
+}
+
+// configureNode overwrites the app.toml and config.toml of the node at home with init.app
+// and init.config of config.yml, and runs the post init handler.
+// the configs are re-created from their defaults first, so only the current changes apply.
+func (c *Chain) configureNode(home string, conf conf.Config) error {
+	if err := restoreDefaultConfigs(home); err != nil {
 		return err
 	}
-	configTOMLPath, err := c.ConfigTOMLPath()
-	if err != nil {
-		return err
-	}
-
-	appconfigs := []struct {
-		ec      confile.EncodingCreator
-		path    string
-		changes map[string]interface{}
-	}{
-		{confile.DefaultJSONEncodingCreator, genesisPath, conf.Genesis},
-		{confile.DefaultTOMLEncodingCreator, appTOMLPath, conf.Init.App},
-		{confile.DefaultTOMLEncodingCreator, configTOMLPath, conf.Init.Config},
+	appconfigs := []configChanges{
+		{confile.DefaultTOMLEncodingCreator, filepath.Join(home, "config/app.toml"), conf.Init.App},
+		{confile.DefaultTOMLEncodingCreator, filepath.Join(home, "config/config.toml"), conf.Init.Config},
 	}
 
 	for _, ac := range appconfigs {
-		cf := confile.New(ac.ec, ac.path)
-		var conf map[string]interface{}
-		if err := cf.Load(&conf); err != nil {
+		if err := ac.apply(); err != nil {
 			return err
 		}
-		if err := mergo.Merge(&conf, ac.changes, mergo.WithOverride); err != nil {
+	}
+
+	// run post init handler
+	return c.plugin.PostInit(home, conf)
+}
+
+// saveDefaultConfigs keeps a copy of the configs of the node at home created by its init.
+func saveDefaultConfigs(home string) error {
+	for _, name := range nodeConfigFiles {
+		path := filepath.Join(home, "config", name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		if err := copy.Copy(path, filepath.Join(home, defaultConfigDir, name)); err != nil {
 			return err
 		}
-		if err := cf.Save(conf); err != nil {
+	}
+	return nil
+}
+
+// restoreDefaultConfigs re-creates the configs of the node at home from the copies kept by
+// saveDefaultConfigs. the nodes initialized before the copies were kept are left unchanged.
+func restoreDefaultConfigs(home string) error {
+	for _, name := range nodeConfigFiles {
+		path := filepath.Join(home, defaultConfigDir, name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		if err := copy.Copy(path, filepath.Join(home, "config", name)); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	// run post init handler
-	return c.plugin.PostInit(home, conf)
+// configChanges holds the changes of config.yml to a config file of a node.
+type configChanges struct {
+	ec      confile.EncodingCreator
+	path    string
+	changes map[string]interface{}
+}
+
+// apply merges the changes into the config file.
+func (ac configChanges) apply() error {
+	cf := confile.New(ac.ec, ac.path)
+	var conf map[string]interface{}
+	if err := cf.Load(&conf); err != nil {
+		return err
+	}
+	if err := mergo.Merge(&conf, ac.changes, mergo.WithOverride); err != nil {
+		return err
+	}
+	return cf.Save(conf)
 }
 
 // InitAccounts initializes the chain accounts and creates validator gentxs
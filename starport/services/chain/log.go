@@ -83,3 +83,12 @@ func (c *Chain) genPrefix(logType logType) string {
 		New(prefix.Name, prefixgen.Common(prefixgen.Color(prefix.Color))...).
 		Gen(c.app.Name)
 }
+
+// genNodePrefix generates the prefix of the logs of the daemon of the node of the validator with name.
+func (c *Chain) genNodePrefix(name string) string {
+	prefix := prefixes[logAppd]
+
+	return prefixgen.
+		New(prefix.Name+" "+name, prefixgen.Common(prefixgen.Color(prefix.Color))...).
+		Gen(c.app.Name)
+}
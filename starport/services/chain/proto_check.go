@@ -0,0 +1,109 @@
+package chain
+
+import (
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/mattn/go-zglob"
+	"github.com/tendermint/starport/starport/pkg/protocheck"
+)
+
+// ProtoCheck is the result of the checks of the proto files of an app.
+type ProtoCheck struct {
+	// Tag is the git tag of the release the proto files are compared to,
+	// it's empty when the app has no tags.
+	Tag string
+
+	// Lint holds the lint issues of the proto files.
+	Lint []protocheck.Issue
+
+	// Breaking holds the wire-breaking changes made since the release.
+	Breaking []protocheck.Issue
+}
+
+// CheckProto lints the proto files of the app and compares them to the ones of the last release,
+// found from the git tags of the app, to detect wire-breaking changes.
+func (c *Chain) CheckProto() (ProtoCheck, error) {
+	var check ProtoCheck
+
+	conf, err := c.Config()
+	if err != nil {
+		return check, err
+	}
+	protoPath := filepath.Join(c.app.Path, conf.Build.Proto.Path)
+
+	paths, err := zglob.Glob(protoPath + "/**/*.proto")
+	if err != nil {
+		return check, err
+	}
+	var current []*protocheck.File
+	for _, p := range paths {
+		rel, err := filepath.Rel(protoPath, p)
+		if err != nil {
+			return check, err
+		}
+		content, err := ioutil.ReadFile(p)
+		if err != nil {
+			return check, err
+		}
+		f, err := protocheck.Parse(filepath.ToSlash(rel), content)
+		if err != nil {
+			return check, err
+		}
+		current = append(current, f)
+	}
+	check.Lint = protocheck.Lint(current...)
+
+	if c.sourceVersion.hash == "" {
+		return check, nil
+	}
+	check.Tag = c.sourceVersion.tag
+
+	previous, err := c.releasedProto(filepath.ToSlash(conf.Build.Proto.Path))
+	if err != nil {
+		return check, err
+	}
+	check.Breaking = protocheck.Breaking(previous, current)
+	return check, nil
+}
+
+// releasedProto parses the proto files of the app at the last release.
+func (c *Chain) releasedProto(protoDir string) ([]*protocheck.File, error) {
+	repo, err := git.PlainOpen(c.app.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := repo.CommitObject(plumbing.NewHash(c.sourceVersion.hash))
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	var files []*protocheck.File
+	err = tree.Files().ForEach(func(file *object.File) error {
+		rel := strings.TrimPrefix(file.Name, protoDir+"/")
+		if rel == file.Name || path.Ext(file.Name) != ".proto" {
+			return nil
+		}
+		content, err := file.Contents()
+		if err != nil {
+			return err
+		}
+		f, err := protocheck.Parse(rel, []byte(content))
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,227 @@
+package chain
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/starport/starport/pkg/cmdrunner"
+	"github.com/tendermint/starport/starport/pkg/cmdrunner/step"
+)
+
+const (
+	// releaseDir is the dir of the app where the release tarballs are created.
+	releaseDir = "release"
+
+	// releaseChecksumsFile lists the SHA-256 of the release tarballs.
+	releaseChecksumsFile = "checksums.txt"
+)
+
+// releaseTarget is a platform to build release binaries for.
+type releaseTarget struct {
+	goos, goarch string
+}
+
+func (t releaseTarget) String() string {
+	return t.goos + "_" + t.goarch
+}
+
+// parseReleaseTargets parses targets given in the GOOS:GOARCH format, e.g.: linux:amd64.
+// the host is targeted when no targets are given.
+func parseReleaseTargets(targets []string) ([]releaseTarget, error) {
+	if len(targets) == 0 {
+		return []releaseTarget{{runtime.GOOS, runtime.GOARCH}}, nil
+	}
+	var parsed []releaseTarget
+	for _, target := range targets {
+		platform := strings.Split(target, ":")
+		if len(platform) != 2 || platform[0] == "" || platform[1] == "" {
+			return nil, fmt.Errorf("invalid target %s, use the GOOS:GOARCH format, e.g.: linux:amd64", target)
+		}
+		parsed = append(parsed, releaseTarget{platform[0], platform[1]})
+	}
+	return parsed, nil
+}
+
+// BuildRelease cross-compiles the binaries of the app for targets, in the GOOS:GOARCH format,
+// and packages them into a tarball per target in the release dir of the app, along with a
+// checksums file. the host is targeted when no targets are given.
+// it returns the path of the release dir.
+func (c *Chain) BuildRelease(ctx context.Context, targets ...string) (releasePath string, err error) {
+	parsedTargets, err := parseReleaseTargets(targets)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.setup(ctx); err != nil {
+		return "", err
+	}
+
+	if err := c.buildProto(ctx); err != nil {
+		return "", err
+	}
+
+	releasePath = filepath.Join(c.app.Path, releaseDir)
+	if err := os.RemoveAll(releasePath); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(releasePath, 0755); err != nil {
+		return "", err
+	}
+
+	version := c.sourceVersion.tag
+	if version == "" {
+		version = "dev"
+	}
+
+	outPath, err := ioutil.TempDir("", "starport-release")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(outPath)
+
+	var checksums bytes.Buffer
+	for _, target := range parsedTargets {
+		fmt.Fprintf(c.stdLog(logStarport).out, "🛠️  Building the app for %s/%s...\n", target.goos, target.goarch)
+
+		binaries, err := c.buildReleaseBinaries(ctx, target, outPath)
+		if err != nil {
+			return "", err
+		}
+
+		tarball := fmt.Sprintf("%s_%s_%s.tar.gz", c.app.Name, version, target)
+		sum, err := tarGzip(filepath.Join(releasePath, tarball), binaries...)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&checksums, "%s  %s\n", sum, tarball)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(releasePath, releaseChecksumsFile), checksums.Bytes(), 0644); err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(c.stdLog(logStarport).out, "🗃  Release created. Find the tarballs in: %s\n", infoColor(releasePath))
+	return releasePath, nil
+}
+
+// buildReleaseBinaries builds the binaries of the app for target into outPath with the same
+// flags used to install them. it returns the paths of the binaries.
+func (c *Chain) buildReleaseBinaries(ctx context.Context, target releaseTarget, outPath string) ([]string, error) {
+	binaries, err := c.appBinaries()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		buildErr = &bytes.Buffer{}
+		paths    []string
+		steps    step.Steps
+	)
+	captureBuildErr := func(err error) error {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &CannotBuildAppError{errors.New(buildErr.String())}
+		}
+		return err
+	}
+
+	for _, binary := range binaries {
+		name := binary.name
+		if target.goos == "windows" {
+			name += ".exe"
+		}
+		binaryPath := filepath.Join(outPath, name)
+		paths = append(paths, binaryPath)
+
+		// cgo is disabled by default to cross-compile.
+		buildOptions, err := c.goBuildOptions(
+			binary.mainPath,
+			binaryPath,
+			"GOOS="+target.goos,
+			"GOARCH="+target.goarch,
+			"CGO_ENABLED=0",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		steps.Add(step.New(step.NewOptions().
+			Add(buildOptions...).
+			Add(step.PostExec(captureBuildErr)).
+			Add(c.stdSteps(logStarport)...).
+			Add(step.Stderr(buildErr))...,
+		))
+	}
+
+	if err := cmdrunner.
+		New(c.cmdOptions()...).
+		Run(ctx, steps...); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// tarGzip creates a gzipped tarball at path holding the files at its root, the files are
+// removed once added. it returns the SHA-256 of the tarball.
+func tarGzip(path string, files ...string) (checksum string, err error) {
+	tarball, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer tarball.Close()
+
+	var (
+		hash = sha256.New()
+		gzw  = gzip.NewWriter(io.MultiWriter(tarball, hash))
+		tw   = tar.NewWriter(gzw)
+	)
+	for _, file := range files {
+		if err := addTarFile(tw, file); err != nil {
+			return "", err
+		}
+		if err := os.Remove(file); err != nil {
+			return "", err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gzw.Close(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func addTarFile(tw *tar.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, file)
+	return err
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/tendermint/starport/starport/pkg/cmdrunner"
 	"github.com/tendermint/starport/starport/pkg/cmdrunner/step"
 	"github.com/tendermint/starport/starport/pkg/cosmosfaucet"
+	"github.com/tendermint/starport/starport/pkg/cosmosver"
 	"github.com/tendermint/starport/starport/pkg/fswatcher"
 	"github.com/tendermint/starport/starport/pkg/xexec"
 	"github.com/tendermint/starport/starport/pkg/xhttp"
@@ -56,6 +57,7 @@ var (
 type serveOptions struct {
 	forceReset bool
 	resetOnce  bool
+	validators int
 }
 
 func newServeOption() serveOptions {
@@ -82,6 +84,14 @@ func ServeResetOnce() ServeOption {
 	}
 }
 
+// ServeValidators runs a local testnet of n validators, the validators listed in config.yml
+// are completed with generated ones.
+func ServeValidators(n int) ServeOption {
+	return func(c *serveOptions) {
+		c.validators = n
+	}
+}
+
 // Serve serves an app.
 func (c *Chain) Serve(ctx context.Context, options ...ServeOption) error {
 	serveOptions := newServeOption()
@@ -101,6 +111,13 @@ func (c *Chain) Serve(ctx context.Context, options ...ServeOption) error {
 		return err
 	}
 
+	if serveOptions.validators < 0 {
+		return fmt.Errorf("invalid number of validators %d", serveOptions.validators)
+	}
+	if serveOptions.validators > 1 && !c.Version.Major().Is(cosmosver.Stargate) {
+		return errors.New("multiple validators are only supported by Stargate apps")
+	}
+
 	// initialize the relayer if application supports it so, secret.yml
 	// can be generated and watched for changes.
 	if err := c.checkIBCRelayerSupport(); err == nil {
@@ -151,7 +168,7 @@ func (c *Chain) Serve(ctx context.Context, options ...ServeOption) error {
 				shouldReset := serveOptions.forceReset || serveOptions.resetOnce
 
 				// serve the app.
-				err = c.serve(serveCtx, shouldReset)
+				err = c.serve(serveCtx, shouldReset, serveOptions.validators)
 				serveOptions.resetOnce = false
 
 				switch {
@@ -257,9 +274,15 @@ func (c *Chain) watchAppBackend(ctx context.Context) error {
 		ctx,
 		append(appBackendSourceWatchPaths, appBackendConfigWatchPaths...),
 		fswatcher.Workdir(c.app.Path),
-		fswatcher.OnChange(c.refreshServe),
+		fswatcher.OnChange(func() {
+			// the app is only reloaded by changes of its backend, the errors are reported by the reload.
+			changes, err := c.detectChanges()
+			if err != nil || changes.any() {
+				c.refreshServe()
+			}
+		}),
 		fswatcher.IgnoreHidden(),
-		fswatcher.IgnoreExt(ignoredExts...),
+		fswatcher.IgnoreExt(append(ignoredExts, frontendExts...)...),
 	)
 }
 
@@ -272,12 +295,23 @@ func (c *Chain) cmdOptions() []cmdrunner.Option {
 // serve performs the operations to serve the blockchain: build, init and start
 // if the chain is already initialized and the file didn't changed, the app is directly started
 // if the files changed, the state is imported
-func (c *Chain) serve(ctx context.Context, forceReset bool) error {
+// the chain runs validators nodes, the validators of config.yml are used when validators is 0.
+func (c *Chain) serve(ctx context.Context, forceReset bool, validators int) error {
 	conf, err := c.Config()
 	if err != nil {
 		return &CannotBuildAppError{err}
 	}
 
+	extraValidators := extraValidators(conf, validators)
+	if len(extraValidators) > 0 && !c.Version.Major().Is(cosmosver.Stargate) {
+		return &CannotBuildAppError{errors.New("multiple validators are only supported by Stargate apps")}
+	}
+
+	nodes, err := c.savedValidatorNodes()
+	if err != nil {
+		return err
+	}
+
 	commands, err := c.Commands(ctx)
 	if err != nil {
 		return err
@@ -288,6 +322,12 @@ func (c *Chain) serve(ctx context.Context, forceReset bool) error {
 		return err
 	}
 
+	// classify the changes since last serve by what they require
+	changes, err := c.detectChanges()
+	if err != nil {
+		return &CannotBuildAppError{err}
+	}
+
 	// isInit determines if the app is initialized
 	var isInit bool
 
@@ -298,34 +338,24 @@ func (c *Chain) serve(ctx context.Context, forceReset bool) error {
 		return err
 	}
 	if isInit {
-		configModified, err := dirchange.HasDirChecksumChanged(c.app.Path, appBackendConfigWatchPaths, saveDir, configChecksum)
-		if err != nil {
-			return err
-		}
+		// the validators of the chain are set in its genesis.
+		validatorsModified := !sameValidators(nodes, extraValidators)
 
-		if forceReset || configModified {
+		if forceReset || changes.config || validatorsModified {
 			// if forceReset is set, we consider the app as being not initialized
 			fmt.Fprintln(c.stdLog(logStarport).out, "🔄 Resetting the app state...")
 			isInit = false
 		}
 	}
 
-	// check if source has been modified since last serve
-	// if the state must not be reset but the source has changed, we rebuild the chain and import the exported state
-	sourceModified, err := dirchange.HasDirChecksumChanged(c.app.Path, appBackendSourceWatchPaths, saveDir, sourceChecksum)
-	if err != nil {
-		return err
-	}
-
 	// we also consider the binary in the checksum to ensure the binary has not been changed by a third party
 	var binaryModified bool
-	binaryName, err := c.Binary()
+	binaryPath, err := c.BinaryPath()
 	if err != nil {
 		return err
 	}
-	binaryPath, err := exec.LookPath(binaryName)
-	if err != nil {
-		if !errors.Is(err, exec.ErrNotFound) {
+	if _, err := os.Stat(binaryPath); err != nil {
+		if !os.IsNotExist(err) {
 			return err
 		}
 		binaryModified = true
@@ -336,7 +366,8 @@ func (c *Chain) serve(ctx context.Context, forceReset bool) error {
 		}
 	}
 
-	appModified := sourceModified || binaryModified
+	// if the state must not be reset but the app has changed, we rebuild the chain and import the exported state
+	appModified := changes.proto || changes.source || changes.deps || binaryModified
 
 	// check if exported genesis exists
 	exportGenesisExists := true
@@ -352,19 +383,23 @@ func (c *Chain) serve(ctx context.Context, forceReset bool) error {
 
 	// build phase
 	if !isInit || appModified {
-		// build proto
-		if err := c.buildProto(ctx); err != nil {
-			return err
+		// build proto only when the proto files changed
+		if !isInit || changes.proto {
+			if err := c.buildProto(ctx); err != nil {
+				return err
+			}
 		}
 
-		// build the blockchain app
-		buildSteps, err := c.buildSteps()
-		if err != nil {
-			return err
+		// tidy the dependencies only when the imports changed, the generated code may import new packages.
+		tidy := !isInit || changes.deps
+		if !tidy && changes.proto {
+			if tidy, err = c.hasDepsChanged(); err != nil {
+				return err
+			}
 		}
-		if err := cmdrunner.
-			New(c.cmdOptions()...).
-			Run(ctx, buildSteps...); err != nil {
+
+		// build the blockchain app, or restore it if it was built from the same sources before.
+		if err := c.buildBinaries(ctx, tidy); err != nil {
 			return err
 		}
 	}
@@ -383,6 +418,19 @@ func (c *Chain) serve(ctx context.Context, forceReset bool) error {
 		if err := c.InitAccounts(ctx, conf); err != nil {
 			return err
 		}
+
+		// initialize the nodes of the other validators
+		if nodes, err = c.newValidatorNodes(extraValidators); err != nil {
+			return err
+		}
+		if err := c.initValidatorNodes(ctx, conf, nodes); err != nil {
+			return err
+		}
+
+		// the contracts deployed to the previous state are gone
+		if err := c.resetDeployedContracts(); err != nil {
+			return err
+		}
 	} else if appModified {
 		// if the chain is already initialized but the source has been modified
 		// we reset the chain database and import the genesis state
@@ -395,19 +443,26 @@ func (c *Chain) serve(ctx context.Context, forceReset bool) error {
 		if err := c.importChainState(); err != nil {
 			return err
 		}
+
+		if err := c.importValidatorNodesState(ctx, nodes); err != nil {
+			return err
+		}
 	} else {
 		fmt.Fprintln(c.stdLog(logStarport).out, "▶️  Restarting existing app...")
 	}
 
-	// save checksums
-	if err := dirchange.SaveDirChecksum(c.app.Path, appBackendConfigWatchPaths, saveDir, configChecksum); err != nil {
-		return err
-	}
-	if err := dirchange.SaveDirChecksum(c.app.Path, appBackendSourceWatchPaths, saveDir, sourceChecksum); err != nil {
-		return err
+	// the app isn't initialized again when only init.app or init.config changed, the configs
+	// of the nodes are updated instead.
+	if isInit && changes.nodeConfig {
+		fmt.Fprintln(c.stdLog(logStarport).out, "🔧 Updating the node configs...")
+
+		if err := c.configureNodes(ctx, conf, nodes); err != nil {
+			return &CannotBuildAppError{err}
+		}
 	}
-	binaryPath, err = exec.LookPath(binaryName)
-	if err != nil {
+
+	// save checksums
+	if err := c.saveChecksums(); err != nil {
 		return err
 	}
 	if err := dirchange.SaveDirChecksum("", []string{binaryPath}, saveDir, binaryChecksum); err != nil {
@@ -415,10 +470,10 @@ func (c *Chain) serve(ctx context.Context, forceReset bool) error {
 	}
 
 	// start the blockchain
-	return c.start(ctx, conf)
+	return c.start(ctx, conf, nodes)
 }
 
-func (c *Chain) start(ctx context.Context, conf conf.Config) error {
+func (c *Chain) start(ctx context.Context, conf conf.Config, nodes []validatorNode) error {
 	commands, err := c.Commands(ctx)
 	if err != nil {
 		return err
@@ -429,6 +484,16 @@ func (c *Chain) start(ctx context.Context, conf conf.Config) error {
 	// start the blockchain.
 	g.Go(func() error { return c.plugin.Start(ctx, commands, conf) })
 
+	// start the nodes of the other validators.
+	for _, node := range nodes {
+		node := node
+		runner, err := c.validatorCommands(ctx, node)
+		if err != nil {
+			return err
+		}
+		g.Go(func() error { return c.plugin.Start(ctx, runner, node.config(conf)) })
+	}
+
 	// run relayer.
 	go func() {
 		if err := c.initRelayer(ctx, conf); err != nil && ctx.Err() == nil {
@@ -436,6 +501,13 @@ func (c *Chain) start(ctx context.Context, conf conf.Config) error {
 		}
 	}()
 
+	// deploy the wasm contracts.
+	go func() {
+		if err := c.deployContracts(ctx, commands, conf); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(c.stdLog(logStarport).err, "could not deploy wasm contracts: %s\n", err)
+		}
+	}()
+
 	// start the faucet if enabled.
 	faucet, err := c.Faucet(ctx)
 	isFaucetEnabled := err != ErrFaucetIsNotEnabled
@@ -462,6 +534,9 @@ func (c *Chain) start(ctx context.Context, conf conf.Config) error {
 	// print the server addresses.
 	fmt.Fprintf(c.stdLog(logStarport).out, "🌍 Running a Cosmos '%[1]v' app with Tendermint at %s.\n", c.app.Name, xurl.HTTP(conf.Servers.RPCAddr))
 	fmt.Fprintf(c.stdLog(logStarport).out, "🌍 Running a server at %s (LCD)\n", xurl.HTTP(conf.Servers.APIAddr))
+	for _, node := range nodes {
+		fmt.Fprintf(c.stdLog(logStarport).out, "🌍 Running the validator '%s' with Tendermint at %s\n", node.Validator.Name, xurl.HTTP(node.Servers.RPCAddr))
+	}
 
 	if isFaucetEnabled {
 		fmt.Fprintf(c.stdLog(logStarport).out, "🌍 Running a faucet at http://0.0.0.0:%d\n", conf.Faucet.Port)
@@ -536,7 +611,7 @@ func (c *Chain) runDevServer(ctx context.Context) error {
 		AppBackendAddr:  xurl.HTTP(config.Servers.APIAddr),
 		AppFrontendAddr: xurl.HTTP(config.Servers.FrontendAddr),
 	} // TODO get vals from const
-	handler, err := newDevHandler(c.app, conf, grpcHandler)
+	handler, err := newDevHandler(c.app, conf, grpcHandler, c.Contracts)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,376 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/otiai10/copy"
+	"github.com/pelletier/go-toml"
+	conf "github.com/tendermint/starport/starport/chainconf"
+	"github.com/tendermint/starport/starport/pkg/availableport"
+	chaincmdrunner "github.com/tendermint/starport/starport/pkg/chaincmd/runner"
+)
+
+const (
+	// validatorsDir is the dir of the homes of the nodes of the validators other than the
+	// validator of the served node.
+	validatorsDir = "validators"
+
+	// validatorsFile lists the nodes of these validators.
+	validatorsFile = "validators.json"
+)
+
+// validatorNode is the node of a validator other than the validator of the served node.
+type validatorNode struct {
+	Validator conf.Validator `json:"validator"`
+	Home      string         `json:"home"`
+	Servers   conf.Servers   `json:"servers"`
+}
+
+// config returns conf with the servers of the node.
+func (n validatorNode) config(conf conf.Config) conf.Config {
+	conf.Servers = n.Servers
+	return conf
+}
+
+// extraValidators returns the validators other than the validator of the served node: the
+// ones listed in config.yml, and generated ones staking as much as the validator of the served
+// node, so the chain has count validators. the validators of config.yml are used when count is 0.
+func extraValidators(config conf.Config, count int) []conf.Validator {
+	var validators []conf.Validator
+	if len(config.Validators) > 1 {
+		validators = append(validators, config.Validators[1:]...)
+	}
+	if count == 0 {
+		return validators
+	}
+	if count-1 < len(validators) {
+		return validators[:count-1]
+	}
+
+	names := map[string]bool{config.Validator.Name: true}
+	for _, validator := range validators {
+		names[validator.Name] = true
+	}
+	for i := 1; len(validators) < count-1; i++ {
+		name := fmt.Sprintf("validator%d", i)
+		if names[name] {
+			continue
+		}
+		validators = append(validators, conf.Validator{Name: name, Staked: config.Validator.Staked})
+	}
+	return validators
+}
+
+// sameValidators checks if nodes are the nodes of validators.
+func sameValidators(nodes []validatorNode, validators []conf.Validator) bool {
+	if len(nodes) != len(validators) {
+		return false
+	}
+	for i, node := range nodes {
+		if node.Validator != validators[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// newValidatorNodes returns the nodes of validators with their homes and distinct ports.
+func (c *Chain) newValidatorNodes(validators []conf.Validator) ([]validatorNode, error) {
+	if len(validators) == 0 {
+		return nil, nil
+	}
+
+	savePath, err := c.chainSavePath()
+	if err != nil {
+		return nil, err
+	}
+
+	// each node has its rpc, p2p, grpc, api and pprof ports.
+	const portsPerNode = 5
+	ports, err := availableport.Find(len(validators) * portsPerNode)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []validatorNode
+	for i, validator := range validators {
+		address := func(j int) string {
+			return fmt.Sprintf("0.0.0.0:%d", ports[i*portsPerNode+j])
+		}
+		nodes = append(nodes, validatorNode{
+			Validator: validator,
+			Home:      filepath.Join(savePath, validatorsDir, strconv.Itoa(i+1)),
+			Servers: conf.Servers{
+				RPCAddr:  address(0),
+				P2PAddr:  address(1),
+				GRPCAddr: address(2),
+				APIAddr:  address(3),
+				ProfAddr: address(4),
+			},
+		})
+	}
+	return nodes, nil
+}
+
+// savedValidatorNodes returns the nodes of the validators initialized by the previous serve.
+func (c *Chain) savedValidatorNodes() ([]validatorNode, error) {
+	savePath, err := c.chainSavePath()
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(filepath.Join(savePath, validatorsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var nodes []validatorNode
+	err = json.Unmarshal(content, &nodes)
+	return nodes, err
+}
+
+func (c *Chain) saveValidatorNodes(nodes []validatorNode) error {
+	savePath, err := c.chainSavePath()
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(nodes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(savePath, validatorsFile), content, 0644)
+}
+
+// validatorCommands returns the runner executing commands on the chain's binary with node.
+func (c *Chain) validatorCommands(ctx context.Context, node validatorNode) (chaincmdrunner.Runner, error) {
+	return c.commandsAt(ctx, node.Home, c.genNodePrefix(node.Validator.Name))
+}
+
+// initValidatorNodes initializes the nodes of the validators other than the validator of the
+// served node, once the served node is initialized with its accounts and gentx.
+// the validators are funded with their stake in the genesis of the served node, and their gentxs
+// are collected into it. this genesis is then shared by all the nodes, that are peers of each other.
+func (c *Chain) initValidatorNodes(ctx context.Context, conf conf.Config, nodes []validatorNode) error {
+	savePath, err := c.chainSavePath()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(savePath, validatorsDir)); err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return c.saveValidatorNodes(nil)
+	}
+
+	fmt.Fprintf(c.stdLog(logStarport).out, "💿 Initializing %d more validators...\n", len(nodes))
+
+	commands, err := c.Commands(ctx)
+	if err != nil {
+		return err
+	}
+	home, err := c.Home()
+	if err != nil {
+		return err
+	}
+	genesisPath, err := c.GenesisPath()
+	if err != nil {
+		return err
+	}
+
+	runners := make([]chaincmdrunner.Runner, len(nodes))
+	for i, node := range nodes {
+		runner, err := c.validatorCommands(ctx, node)
+		if err != nil {
+			return err
+		}
+		if err := c.initNode(ctx, runner, node.Home, node.Validator.Name, node.config(conf)); err != nil {
+			return err
+		}
+
+		account, err := runner.AddAccount(ctx, node.Validator.Name, "")
+		if err != nil {
+			return err
+		}
+		if err := commands.AddGenesisAccount(ctx, account.Address, node.Validator.Staked); err != nil {
+			return err
+		}
+		runners[i] = runner
+	}
+
+	// the gentxs are created once all the validators are funded.
+	for i, node := range nodes {
+		if err := copy.Copy(genesisPath, filepath.Join(node.Home, "config/genesis.json")); err != nil {
+			return err
+		}
+		gentxPath, err := c.plugin.Gentx(ctx, runners[i], Validator{
+			Name:          node.Validator.Name,
+			Moniker:       node.Validator.Name,
+			StakingAmount: node.Validator.Staked,
+		})
+		if err != nil {
+			return err
+		}
+		if err := copy.Copy(gentxPath, filepath.Join(home, "config/gentx", filepath.Base(gentxPath))); err != nil {
+			return err
+		}
+	}
+
+	if err := commands.CollectGentxs(ctx); err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if err := copy.Copy(genesisPath, filepath.Join(node.Home, "config/genesis.json")); err != nil {
+			return err
+		}
+	}
+
+	if err := c.connectValidatorNodes(ctx, conf, commands, runners, nodes); err != nil {
+		return err
+	}
+	return c.saveValidatorNodes(nodes)
+}
+
+// connectValidatorNodes makes the served node and the nodes of the other validators
+// persistent peers of each other.
+func (c *Chain) connectValidatorNodes(
+	ctx context.Context,
+	conf conf.Config,
+	commands chaincmdrunner.Runner,
+	runners []chaincmdrunner.Runner,
+	nodes []validatorNode,
+) error {
+	home, err := c.Home()
+	if err != nil {
+		return err
+	}
+
+	homes := []string{home}
+	p2pAddresses := []string{conf.Servers.P2PAddr}
+	for _, node := range nodes {
+		homes = append(homes, node.Home)
+		p2pAddresses = append(p2pAddresses, node.Servers.P2PAddr)
+	}
+
+	var peers []string
+	for i, runner := range append([]chaincmdrunner.Runner{commands}, runners...) {
+		nodeID, err := runner.ShowNodeID(ctx)
+		if err != nil {
+			return err
+		}
+		port, err := addressPort(p2pAddresses[i])
+		if err != nil {
+			return err
+		}
+		peers = append(peers, fmt.Sprintf("%s@127.0.0.1:%s", nodeID, port))
+	}
+
+	for i, home := range homes {
+		var otherPeers []string
+		for j, peer := range peers {
+			if i != j {
+				otherPeers = append(otherPeers, peer)
+			}
+		}
+		if err := setConfigTOML(home, map[string]interface{}{
+			"p2p.persistent_peers": strings.Join(otherPeers, ","),
+			// the nodes are peers on the same host.
+			"p2p.allow_duplicate_ip": true,
+			"p2p.addr_book_strict":   false,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configureNodes overwrites the configs of the served node and the nodes of the other
+// validators with init.app and init.config of config.yml.
+// the configs are re-created from their defaults, the nodes are connected to each other again.
+func (c *Chain) configureNodes(ctx context.Context, conf conf.Config, nodes []validatorNode) error {
+	home, err := c.Home()
+	if err != nil {
+		return err
+	}
+	if err := c.configureNode(home, conf); err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if err := c.configureNode(node.Home, node.config(conf)); err != nil {
+			return err
+		}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	commands, err := c.Commands(ctx)
+	if err != nil {
+		return err
+	}
+	runners := make([]chaincmdrunner.Runner, len(nodes))
+	for i, node := range nodes {
+		if runners[i], err = c.validatorCommands(ctx, node); err != nil {
+			return err
+		}
+	}
+	return c.connectValidatorNodes(ctx, conf, commands, runners, nodes)
+}
+
+// importValidatorNodesState resets the databases of nodes and imports the state exported
+// from the served node into them.
+func (c *Chain) importValidatorNodesState(ctx context.Context, nodes []validatorNode) error {
+	exportedGenesisPath, err := c.exportedGenesisPath()
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		runner, err := c.validatorCommands(ctx, node)
+		if err != nil {
+			return err
+		}
+		if err := runner.UnsafeReset(ctx); err != nil {
+			return err
+		}
+		if err := copy.Copy(exportedGenesisPath, filepath.Join(node.Home, "config/genesis.json")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setConfigTOML sets the values of the config.toml of the node at home.
+func setConfigTOML(home string, values map[string]interface{}) error {
+	path := filepath.Join(home, "config/config.toml")
+	config, err := toml.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	for key, value := range values {
+		config.Set(key, value)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = config.WriteTo(file)
+	return err
+}
+
+// addressPort returns the port of address, the address may have a scheme, e.g.: tcp://0.0.0.0:26657.
+func addressPort(address string) (string, error) {
+	if i := strings.Index(address, "://"); i != -1 {
+		address = address[i+3:]
+	}
+	_, port, err := net.SplitHostPort(address)
+	return port, err
+}
@@ -0,0 +1,216 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	conf "github.com/tendermint/starport/starport/chainconf"
+	chaincmdrunner "github.com/tendermint/starport/starport/pkg/chaincmd/runner"
+	"github.com/tendermint/starport/starport/pkg/gomodule"
+	"github.com/tendermint/starport/starport/pkg/xurl"
+)
+
+const (
+	wasmImport = "github.com/CosmWasm/wasmd"
+
+	// deployedContracts is the file listing the contracts deployed to the chain state.
+	deployedContracts = "wasm_contracts.json"
+)
+
+// DeployedContract is a CosmWasm contract of config.yml deployed to the chain.
+type DeployedContract struct {
+	Path    string `json:"path"`
+	Label   string `json:"label"`
+	CodeID  string `json:"code_id"`
+	Address string `json:"address"`
+}
+
+// Contracts returns the contracts of config.yml deployed to the served chain.
+func (c *Chain) Contracts() []DeployedContract {
+	c.contractsMu.Lock()
+	defer c.contractsMu.Unlock()
+	return append([]DeployedContract{}, c.contracts...)
+}
+
+// deployContracts stores and instantiates the contracts of config.yml once the chain produces blocks.
+// the contracts already deployed to the chain state are not deployed again.
+func (c *Chain) deployContracts(ctx context.Context, commands chaincmdrunner.Runner, conf conf.Config) error {
+	c.contractsMu.Lock()
+	c.contracts = nil
+	c.contractsMu.Unlock()
+
+	if len(conf.Wasm.Contracts) == 0 {
+		return nil
+	}
+	gomod, err := gomodule.ParseAt(c.app.Path)
+	if err != nil {
+		return err
+	}
+	if len(gomodule.FilterRequire(gomod.Require, wasmImport)) == 0 {
+		return errors.New("the wasm module is not imported, import it with: starport module import wasm")
+	}
+
+	deployed, err := c.readDeployedContracts()
+	if err != nil {
+		return err
+	}
+	if err := waitFirstBlock(ctx, xurl.HTTP(conf.Servers.RPCAddr)); err != nil {
+		return err
+	}
+
+	for _, contract := range conf.Wasm.Contracts {
+		dc, ok := findDeployedContract(deployed, contract)
+		if !ok {
+			if dc, err = c.deployContract(ctx, commands, conf, contract); err != nil {
+				return err
+			}
+			deployed = append(deployed, dc)
+			if err := c.writeDeployedContracts(deployed); err != nil {
+				return err
+			}
+		}
+
+		c.contractsMu.Lock()
+		c.contracts = append(c.contracts, dc)
+		c.contractsMu.Unlock()
+
+		fmt.Fprintf(c.stdLog(logStarport).out, "📜 Contract '%s' (code id %s) deployed at %s\n", dc.Label, dc.CodeID, dc.Address)
+	}
+	return nil
+}
+
+// deployContract stores and instantiates a contract.
+func (c *Chain) deployContract(ctx context.Context, commands chaincmdrunner.Runner, conf conf.Config, contract conf.Contract) (DeployedContract, error) {
+	dc := DeployedContract{
+		Path:  contract.Path,
+		Label: contract.Label,
+	}
+
+	from := contract.From
+	if from == "" {
+		from = conf.Validator.Name
+	}
+	var admin string
+	if contract.Admin != "" {
+		account, err := commands.ShowAccount(ctx, contract.Admin)
+		if err != nil {
+			return dc, err
+		}
+		admin = account.Address
+	}
+	msg := []byte("{}")
+	if contract.Msg != nil {
+		var err error
+		if msg, err = json.Marshal(contract.Msg); err != nil {
+			return dc, err
+		}
+	}
+	path := contract.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(c.app.Path, path)
+	}
+
+	var err error
+	if dc.CodeID, err = commands.WasmStore(ctx, from, path); err != nil {
+		return dc, err
+	}
+	dc.Address, err = commands.WasmInstantiate(ctx, from, dc.CodeID, string(msg), contract.Label, admin)
+	return dc, err
+}
+
+func findDeployedContract(deployed []DeployedContract, contract conf.Contract) (DeployedContract, bool) {
+	for _, dc := range deployed {
+		if dc.Path == contract.Path && dc.Label == contract.Label {
+			return dc, true
+		}
+	}
+	return DeployedContract{}, false
+}
+
+func (c *Chain) deployedContractsPath() (string, error) {
+	savePath, err := c.chainSavePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(savePath, deployedContracts), nil
+}
+
+func (c *Chain) readDeployedContracts() ([]DeployedContract, error) {
+	path, err := c.deployedContractsPath()
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var deployed []DeployedContract
+	return deployed, json.Unmarshal(content, &deployed)
+}
+
+func (c *Chain) writeDeployedContracts(deployed []DeployedContract) error {
+	path, err := c.deployedContractsPath()
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(deployed, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+// resetDeployedContracts forgets the deployed contracts when the chain state is reset.
+func (c *Chain) resetDeployedContracts() error {
+	path, err := c.deployedContractsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// waitFirstBlock waits until the node at rpcAddr commits its first block.
+func waitFirstBlock(ctx context.Context, rpcAddr string) error {
+	return backoff.Retry(func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rpcAddr+"/status", nil)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+
+		var status struct {
+			Result struct {
+				SyncInfo struct {
+					LatestBlockHeight string `json:"latest_block_height"`
+				} `json:"sync_info"`
+			} `json:"result"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&status); err != nil {
+			return err
+		}
+		height, _ := strconv.ParseInt(status.Result.SyncInfo.LatestBlockHeight, 10, 64)
+		if height < 1 {
+			return errors.New("no blocks committed yet")
+		}
+		return nil
+	}, backoff.WithContext(backoff.NewConstantBackOff(time.Second), ctx))
+}
@@ -1,7 +1,13 @@
 package services
 
-import "os"
+import (
+	"os"
+	"path/filepath"
+)
 
 var (
 	StarportConfDir = os.ExpandEnv("$HOME/.starport")
+
+	// ToolsDir is the dir caching the tools built by Starport, e.g.: the protoc plugins.
+	ToolsDir = filepath.Join(StarportConfDir, "tools")
 )
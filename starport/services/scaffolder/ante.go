@@ -0,0 +1,85 @@
+package scaffolder
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tendermint/starport/starport/pkg/cosmosver"
+	"github.com/tendermint/starport/starport/pkg/gomodulepath"
+	"github.com/tendermint/starport/starport/templates/ante"
+)
+
+// AddAnteDecorator adds a custom decorator with name to the ante handler of the app.
+// the ante handler is created from the default decorators of the SDK the first time.
+func (s *Scaffolder) AddAnteDecorator(name string) (Changes, error) {
+	var changes Changes
+	if err := checkDecoratorName(name); err != nil {
+		return changes, err
+	}
+	version, err := s.version()
+	if err != nil {
+		return changes, err
+	}
+	if version.Major() == cosmosver.Launchpad {
+		return changes, errors.New("ante decorators are only supported on Stargate")
+	}
+	path, err := gomodulepath.ParseAt(s.path)
+	if err != nil {
+		return changes, err
+	}
+
+	if _, err := os.Stat(filepath.Join(s.path, filepath.Dir(ante.PathAnte), name+".go")); err == nil {
+		return changes, fmt.Errorf("the decorator %s already exists", name)
+	}
+	chainExists := true
+	if _, err := os.Stat(filepath.Join(s.path, ante.PathAnte)); os.IsNotExist(err) {
+		chainExists = false
+	}
+
+	opts := &ante.Options{
+		AppName:       path.Package,
+		ModulePath:    path.RawPath,
+		DecoratorName: name,
+	}
+	run, err := s.newRunner(&changes)
+	if err != nil {
+		return changes, err
+	}
+	if !chainExists {
+		g, err := ante.NewChainStargate(opts)
+		if err != nil {
+			return changes, err
+		}
+		run.With(g)
+	}
+	g, err := ante.NewDecoratorStargate(opts)
+	if err != nil {
+		return changes, err
+	}
+	run.With(g)
+	if err := run.Run(); err != nil {
+		return changes, err
+	}
+	appPath, err := s.appPath()
+	if err != nil {
+		return changes, err
+	}
+	return changes, fmtProject(appPath)
+}
+
+// checkDecoratorName checks that name can be used for the file and type of a decorator.
+// ante is the name of the file of the ante handler.
+func checkDecoratorName(name string) error {
+	if !token.IsIdentifier(name) {
+		return fmt.Errorf("%s is not a valid decorator name, it must be a Go identifier", name)
+	}
+	// Ensure the decorator name is not a Go reserved name, it would generate an incorrect code
+	if isGoReservedWord(name) || name == strings.TrimSuffix(filepath.Base(ante.PathAnte), ".go") {
+		return fmt.Errorf("%s can't be used as a decorator name", name)
+	}
+	return nil
+}
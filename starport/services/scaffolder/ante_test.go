@@ -0,0 +1,22 @@
+package scaffolder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddAnteDecoratorInvalidName(t *testing.T) {
+	s, _ := scaffoldStargateType(t, false, "title")
+
+	for _, name := range []string{"ante", "type", "string", "1fee", "fee-discount", ""} {
+		_, err := s.AddAnteDecorator(name)
+		require.Error(t, err, name)
+	}
+
+	// nothing is generated for the rejected names.
+	_, err := os.Stat(filepath.Join(s.path, "app/ante"))
+	require.True(t, os.IsNotExist(err))
+}
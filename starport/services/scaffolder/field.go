@@ -0,0 +1,89 @@
+package scaffolder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tendermint/starport/starport/pkg/cosmosver"
+	"github.com/tendermint/starport/starport/pkg/gomodulepath"
+	"github.com/tendermint/starport/starport/templates/typed"
+)
+
+// AddField adds fields to the existing type stype of the module.
+// the sources of the type are modified in place so the custom logic added to them is kept.
+func (s *Scaffolder) AddField(moduleName string, stype string, fields ...string) (Changes, error) {
+	var changes Changes
+	version, err := s.version()
+	if err != nil {
+		return changes, err
+	}
+	majorVersion := version.Major()
+	if majorVersion == cosmosver.Launchpad {
+		return changes, errors.New("adding fields to a type is only supported on Stargate")
+	}
+	path, err := gomodulepath.ParseAt(s.path)
+	if err != nil {
+		return changes, err
+	}
+	opts, err := s.addField(path, &changes, moduleName, stype, fields...)
+	if err != nil {
+		return changes, err
+	}
+	appPath, err := s.appPath()
+	if err != nil {
+		return changes, err
+	}
+	if err := s.finish(appPath, path.RawPath, majorVersion); err != nil {
+		return changes, err
+	}
+	return changes, s.recordFields(*opts, fields...)
+}
+
+// addField runs the generator adding fields to stype without generating the proto code
+// and formatting the source. it returns the options of the generator.
+func (s *Scaffolder) addField(path gomodulepath.Path, changes *Changes, moduleName, stype string, fields ...string) (*typed.Options, error) {
+	// If no module is provided, the type is in the app's module
+	if moduleName == "" {
+		moduleName = path.Package
+	}
+	ok, err := isTypeCreated(s.path, moduleName, stype)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("the type %s doesn't exist in the module %s", stype, moduleName)
+	}
+
+	if len(fields) == 0 {
+		return nil, errors.New("at least one field is required")
+	}
+	tfields, err := parseFields(fields)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range tfields {
+		// the existing objects would be missing from the checks and the indexes
+		if field.Reference != "" || field.Indexed {
+			return nil, fmt.Errorf("the field %s can't be a reference or be indexed, these fields can only be added when the type is created", field.Name)
+		}
+	}
+
+	opts := &typed.Options{
+		AppName:    path.Package,
+		ModulePath: path.RawPath,
+		ModuleName: moduleName,
+		OwnerName:  owner(path.RawPath),
+		TypeName:   stype,
+		Fields:     tfields,
+	}
+	g, err := typed.NewFieldStargate(opts)
+	if err != nil {
+		return nil, err
+	}
+	run, err := s.newRunner(changes)
+	if err != nil {
+		return nil, err
+	}
+	run.With(g)
+	return opts, run.Run()
+}
@@ -0,0 +1,275 @@
+package scaffolder
+
+import (
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/starport/starport/pkg/cosmosver"
+	"github.com/tendermint/starport/starport/pkg/gomodulepath"
+	"github.com/tendermint/starport/starport/templates/typed"
+)
+
+// scaffoldStargateType scaffolds a Stargate app with the type post in its default module.
+// the code generated from the proto files is replaced by a stub declaring the message of the type,
+// the type is recorded in the manifest of the app when record is true.
+func scaffoldStargateType(t *testing.T, record bool, fields ...string) (*Scaffolder, gomodulepath.Path) {
+	path, err := gomodulepath.Parse("github.com/foo/mars")
+	require.NoError(t, err)
+	appPath := filepath.Join(t.TempDir(), path.Root)
+	require.NoError(t, New("", SdkVersion(cosmosver.Stargate)).generate(path, appPath))
+
+	s := New(appPath)
+	opts, err := s.addType(path, cosmosver.Stargate, &Changes{}, "", "post", fields...)
+	require.NoError(t, err)
+	stub := []byte("package types\n\ntype MsgCreatePost struct{}\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(appPath, "x/mars/types/post.pb.go"), stub, 0644))
+	if record {
+		require.NoError(t, s.recordTypes(cosmosver.Stargate, recordedType{opts, fields}))
+	}
+	return s, path
+}
+
+// formatApp formats the Go files of the app like the scaffolding does once it's done.
+func formatApp(t *testing.T, s *Scaffolder) {
+	err := filepath.Walk(s.path, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if content, err = format.Source(content); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(path, content, 0644)
+	})
+	require.NoError(t, err)
+}
+
+func TestAddField(t *testing.T) {
+	s, path := scaffoldStargateType(t, true, "title")
+	formatApp(t, s)
+
+	var changes Changes
+	opts, err := s.addField(path, &changes, "", "post", "likes:int", "published:bool")
+	require.NoError(t, err)
+	require.Empty(t, changes.Created)
+	require.ElementsMatch(t, []string{
+		"proto/mars/post.proto",
+		"x/mars/types/messages_post.go",
+		"x/mars/keeper/post.go",
+		"x/mars/handler_post.go",
+		"x/mars/client/cli/txPost.go",
+		"x/mars/client/rest/txPost.go",
+		"vue/src/views/Index.vue",
+		"vue/src/api/mars/post.js",
+		"vue/src/components/mars/PostForm.vue",
+		"vue/src/components/mars/PostList.vue",
+		"vue/src/components/mars/PostDetail.vue",
+	}, changes.Modified)
+
+	read := func(path string) string {
+		content, err := ioutil.ReadFile(filepath.Join(s.path, path))
+		require.NoError(t, err)
+		if strings.HasSuffix(path, ".go") {
+			_, err := format.Source(content)
+			require.NoError(t, err, "%s is not valid Go", path)
+		}
+		return string(content)
+	}
+
+	proto := read("proto/mars/post.proto")
+	for message, numbers := range map[string]string{
+		"Post":          "int32 likes = 4;\n  bool published = 5;\n}",
+		"MsgCreatePost": "int32 likes = 3;\n  bool published = 4;\n}",
+		"MsgUpdatePost": "int32 likes = 4;\n  bool published = 5;\n}",
+	} {
+		start := strings.Index(proto, "message "+message+" {")
+		require.NotEqual(t, -1, start, message)
+		body := proto[start:]
+		require.Contains(t, body[:strings.Index(body, "}")+1], numbers, message)
+	}
+
+	messages := read("x/mars/types/messages_post.go")
+	require.Contains(t, messages, "title string, likes int32, published bool)")
+	require.Contains(t, messages, "Likes: likes")
+	require.Contains(t, messages, "Published: published")
+
+	require.Contains(t, read("x/mars/keeper/post.go"), "Likes: msg.Likes")
+	require.Contains(t, read("x/mars/handler_post.go"), "Published: msg.Published")
+
+	cli := read("x/mars/client/cli/txPost.go")
+	require.Contains(t, cli, "[title] [likes] [published]\"")
+	require.Contains(t, cli, "[id] [title] [likes] [published]\"")
+	require.Contains(t, cli, "cobra.ExactArgs(3)")
+	require.Contains(t, cli, "cobra.ExactArgs(4)")
+	require.Contains(t, cli, `"strconv"`)
+	require.Contains(t, cli, "strconv.ParseInt(args[1], 10, 64)")
+	require.Contains(t, cli, "strconv.ParseBool(args[3])")
+	require.Contains(t, cli, "int32(argsLikes)")
+
+	rest := read("x/mars/client/rest/txPost.go")
+	require.Contains(t, rest, "Likes string `json:\"likes\"`")
+	require.Contains(t, rest, "strconv.ParseBool(req.Published)")
+	require.Contains(t, rest, "parsedLikes")
+
+	require.Contains(t, read("vue/src/views/Index.vue"), `['title', 2, 'string'] , ['likes', 3, 'int32'] , ['published', 4, 'bool'] ]"`)
+
+	require.Contains(t, read("vue/src/api/mars/post.js"), `  { name: "title", type: "string", create: 2, update: 3 },
+  { name: "likes", type: "int32", create: 3, update: 4 },
+  { name: "published", type: "bool", create: 4, update: 5 },
+];`)
+
+	form := read("vue/src/components/mars/PostForm.vue")
+	require.Contains(t, form, `    <label>
+      likes
+      <input v-model.number="values.likes" type="number" />
+    </label>
+    <label>
+      published
+      <input v-model="values.published" type="checkbox" />
+    </label>
+    <button type="submit"`)
+	require.Contains(t, form, `    title: item ? item.title : "",
+    likes: item ? item.likes : 0,
+    published: item ? item.published : false,
+  };`)
+
+	list := read("vue/src/components/mars/PostList.vue")
+	require.Contains(t, list, "<th>title</th>\n        <th>likes</th>\n        <th>published</th>\n        <th>creator</th>")
+	require.Contains(t, list, "<td>{{ item.published }}</td>\n        <td>{{ item.creator }}</td>")
+	require.Contains(t, list, `:colspan="5"`)
+
+	require.Contains(t, read("vue/src/components/mars/PostDetail.vue"), "<dd>{{ item.title }}</dd>\n    <dt>likes</dt>\n    <dd>{{ item.likes }}</dd>\n    <dt>published</dt>\n    <dd>{{ item.published }}</dd>\n  </dl>")
+
+	// the fields are recorded with the fields the type was created with, the recorded files
+	// are the files of the app as add-field modified them.
+	require.NoError(t, s.recordFields(*opts, "likes:int", "published:bool"))
+	manifest, err := readGeneratedManifest(s.path)
+	require.NoError(t, err)
+	require.Len(t, manifest.Types, 1)
+	require.Equal(t, []string{"title", "likes:int", "published:bool"}, manifest.Types[0].Fields)
+	for _, path := range changes.Modified {
+		sum, ok := manifest.Types[0].Files[path]
+		if !ok {
+			// the files shared with other types aren't recorded
+			require.False(t, strings.HasPrefix(path, "x/mars/client") || strings.HasPrefix(path, "proto"), path)
+			continue
+		}
+		base, err := readGeneratedBase(s.path, path)
+		require.NoError(t, err)
+		require.Equal(t, checksum(base), sum)
+		content := []byte(read(path))
+		if strings.HasSuffix(path, ".go") {
+			content, err = format.Source(content)
+			require.NoError(t, err)
+		}
+		require.Equal(t, string(content), string(base), path)
+	}
+}
+
+func TestRecordFieldsKeepsEdits(t *testing.T) {
+	s, path := scaffoldStargateType(t, true, "title")
+	formatApp(t, s)
+
+	// an edit of the handler made before adding the fields.
+	handlerPath := "x/mars/handler_post.go"
+	handler, err := ioutil.ReadFile(filepath.Join(s.path, handlerPath))
+	require.NoError(t, err)
+	edit := "\nfunc custom() {}\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(s.path, handlerPath), append(handler, edit...), 0644))
+
+	opts, err := s.addField(path, &Changes{}, "", "post", "likes:int")
+	require.NoError(t, err)
+	require.NoError(t, s.recordFields(*opts, "likes:int"))
+	base, err := readGeneratedBase(s.path, handlerPath)
+	require.NoError(t, err)
+	require.Contains(t, string(base), "Likes:")
+	require.NotContains(t, string(base), "custom")
+
+	// the fields rendered with the type don't conflict with the fields added to it,
+	// the edit is kept.
+	manifest, err := readGeneratedManifest(s.path)
+	require.NoError(t, err)
+	tfields, err := parseFields(manifest.Types[0].Fields)
+	require.NoError(t, err)
+	files, err := renderTypeFiles(cosmosver.Stargate, &typed.Options{
+		AppName:    path.Package,
+		ModulePath: path.RawPath,
+		ModuleName: "mars",
+		OwnerName:  owner(path.RawPath),
+		TypeName:   "post",
+		Fields:     tfields,
+	})
+	require.NoError(t, err)
+	_, _, err = s.regenerateFile(handlerPath, files[handlerPath], manifest.Types[0].Files[handlerPath])
+	require.NoError(t, err)
+	handler, err = ioutil.ReadFile(filepath.Join(s.path, handlerPath))
+	require.NoError(t, err)
+	require.Contains(t, string(handler), "func custom() {}")
+	require.Equal(t, 1, strings.Count(string(handler), "Likes:"))
+}
+
+func TestAddFieldExisting(t *testing.T) {
+	s, path := scaffoldStargateType(t, false, "title")
+
+	_, err := s.addField(path, &Changes{}, "", "post", "title")
+	require.EqualError(t, err, "proto/mars/post.proto: the field title already exists in the message Post")
+}
+
+func TestAddFieldMissingType(t *testing.T) {
+	s, path := scaffoldStargateType(t, false, "title")
+
+	_, err := s.addField(path, &Changes{}, "", "comment", "body")
+	require.EqualError(t, err, "the type comment doesn't exist in the module mars")
+}
+
+func TestRecordFieldsUnrecordedType(t *testing.T) {
+	s, path := scaffoldStargateType(t, false, "title")
+
+	opts, err := s.addField(path, &Changes{}, "", "post", "likes:int")
+	require.NoError(t, err)
+	require.NoError(t, s.recordFields(*opts, "likes:int"))
+	_, err = os.Stat(filepath.Join(s.path, generatedManifestPath))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestAddFieldStrings(t *testing.T) {
+	s, path := scaffoldStargateType(t, false, "title")
+
+	_, err := s.addField(path, &Changes{}, "", "post", "tags:strings")
+	require.NoError(t, err)
+
+	read := func(path string) string {
+		content, err := ioutil.ReadFile(filepath.Join(s.path, path))
+		require.NoError(t, err)
+		if strings.HasSuffix(path, ".go") {
+			_, err := format.Source(content)
+			require.NoError(t, err, "%s is not valid Go", path)
+		}
+		return string(content)
+	}
+
+	require.Contains(t, read("proto/mars/post.proto"), "repeated string tags = 4;")
+	require.Contains(t, read("x/mars/types/messages_post.go"), "title string, tags []string)")
+
+	cli := read("x/mars/client/cli/txPost.go")
+	require.Contains(t, cli, `"strings"`)
+	require.NotContains(t, cli, `"strconv"`)
+	require.Contains(t, cli, `argsTags := strings.Split(args[1], ",")`)
+	require.Contains(t, cli, "[]string(argsTags)")
+
+	rest := read("x/mars/client/rest/txPost.go")
+	require.Contains(t, rest, "Tags []string `json:\"tags\"`")
+	require.Contains(t, rest, "parsedTags := req.Tags")
+
+	require.Contains(t, read("vue/src/api/mars/post.js"), `{ name: "tags", type: "string", repeated: true, create: 3, update: 4 },`)
+	require.Contains(t, read("vue/src/components/mars/PostForm.vue"), `tags: item ? (item.tags || []).join(",") : "",`)
+	require.NotContains(t, read("vue/src/views/Index.vue"), "'tags'")
+}
@@ -0,0 +1,214 @@
+package scaffolder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gobuffalo/genny"
+	"github.com/tendermint/starport/starport/internal/version"
+	"github.com/tendermint/starport/starport/pkg/cosmosver"
+	"github.com/tendermint/starport/starport/templates/typed"
+)
+
+const (
+	// generatedManifestPath is the path of the manifest of the generated files in an app.
+	generatedManifestPath = ".starport/generated.json"
+
+	// generatedBaseDir is the dir of an app holding the generated content of the files,
+	// it's the base of the three-way merges done to regenerate the files.
+	generatedBaseDir = ".starport/generated"
+)
+
+// generatedManifest records the types scaffolded in an app and their generated files.
+type generatedManifest struct {
+	Types []generatedType `json:"types"`
+}
+
+// generatedType records a scaffolded type.
+type generatedType struct {
+	Module string   `json:"module"`
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"`
+
+	// Version is the version of Starport whose templates generated the files.
+	Version string `json:"version"`
+
+	// Files maps the paths of the generated files to the sha256 of their generated content.
+	Files map[string]string `json:"files"`
+}
+
+func (m *generatedManifest) find(module, name string) *generatedType {
+	for i, t := range m.Types {
+		if t.Module == module && t.Name == name {
+			return &m.Types[i]
+		}
+	}
+	return nil
+}
+
+func readGeneratedManifest(appPath string) (generatedManifest, error) {
+	var manifest generatedManifest
+	content, err := ioutil.ReadFile(filepath.Join(appPath, generatedManifestPath))
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return manifest, err
+	}
+	return manifest, json.Unmarshal(content, &manifest)
+}
+
+func writeGeneratedManifest(appPath string, manifest generatedManifest) error {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(appPath, generatedManifestPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// recordTypes records the files generated for the types in the manifest of the app.
+// the files are rendered again to record their generated content, before other types modify them.
+func (s *Scaffolder) recordTypes(majorVersion cosmosver.MajorVersion, types ...recordedType) error {
+	if len(types) == 0 {
+		return nil
+	}
+	manifest, err := readGeneratedManifest(s.path)
+	if err != nil {
+		return err
+	}
+	for _, t := range types {
+		files, err := renderTypeFiles(majorVersion, t.opts)
+		if err != nil {
+			return err
+		}
+		gtype := generatedType{
+			Module:  t.opts.ModuleName,
+			Name:    t.opts.TypeName,
+			Fields:  t.fields,
+			Version: templateVersion(),
+			Files:   make(map[string]string),
+		}
+		for path, content := range files {
+			if err := writeGeneratedBase(s.path, path, content); err != nil {
+				return err
+			}
+			gtype.Files[path] = checksum(content)
+		}
+		if existing := manifest.find(gtype.Module, gtype.Name); existing != nil {
+			*existing = gtype
+		} else {
+			manifest.Types = append(manifest.Types, gtype)
+		}
+	}
+	return writeGeneratedManifest(s.path, manifest)
+}
+
+// recordFields records the fields added with opts to the type in the manifest of the app.
+// the recorded files of the type are modified by add-field the same way the files of the app
+// were, so the edits made to the files of the app are still told apart from the generated code.
+// the types scaffolded before the manifest existed aren't recorded, their fields are unknown.
+func (s *Scaffolder) recordFields(opts typed.Options, fields ...string) error {
+	manifest, err := readGeneratedManifest(s.path)
+	if err != nil {
+		return err
+	}
+	gtype := manifest.find(opts.ModuleName, opts.TypeName)
+	if gtype == nil {
+		return nil
+	}
+	g, err := typed.NewFieldStargate(&opts)
+	if err != nil {
+		return err
+	}
+	files, err := renderFiles(filepath.Join(s.path, generatedBaseDir), g)
+	if err != nil {
+		return err
+	}
+	for path, content := range files {
+		// the files shared with other types aren't recorded
+		if _, ok := gtype.Files[path]; !ok {
+			continue
+		}
+		if err := writeGeneratedBase(s.path, path, content); err != nil {
+			return err
+		}
+		gtype.Files[path] = checksum(content)
+	}
+	gtype.Fields = append(gtype.Fields, fields...)
+	return writeGeneratedManifest(s.path, manifest)
+}
+
+// recordedType is a type to record in the manifest with the fields it was created with.
+type recordedType struct {
+	opts   *typed.Options
+	fields []string
+}
+
+// renderTypeFiles renders the files of a type in memory.
+func renderTypeFiles(majorVersion cosmosver.MajorVersion, opts *typed.Options) (map[string][]byte, error) {
+	g, err := typed.NewFiles(majorVersion, opts)
+	if err != nil {
+		return nil, err
+	}
+	return renderFiles("", g)
+}
+
+// renderFiles renders the files of g in memory, the existing files g modifies are read from
+// root. the Go files are formatted the same way the scaffolded source is.
+func renderFiles(root string, g *genny.Generator) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	run := genny.WetRunner(context.Background())
+	if root != "" {
+		run.Root = root
+	}
+	run.FileFn = func(f genny.File) (genny.File, error) {
+		content, err := ioutil.ReadAll(f)
+		if err != nil {
+			return f, err
+		}
+		if strings.HasSuffix(f.Name(), ".go") {
+			if content, err = format.Source(content); err != nil {
+				return f, err
+			}
+		}
+		files[filepath.ToSlash(f.Name())] = content
+		return f, nil
+	}
+	run.With(g)
+	return files, run.Run()
+}
+
+func readGeneratedBase(appPath, path string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(appPath, generatedBaseDir, path))
+}
+
+func writeGeneratedBase(appPath, path string, content []byte) error {
+	basePath := filepath.Join(appPath, generatedBaseDir, path)
+	if err := os.MkdirAll(filepath.Dir(basePath), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(basePath, content, 0644)
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func templateVersion() string {
+	if version.Version == "" {
+		return "development"
+	}
+	return version.Version
+}
@@ -2,7 +2,6 @@ package scaffolder
 
 import (
 	"context"
-	"os"
 	"path/filepath"
 	"time"
 
@@ -14,7 +13,9 @@ import (
 	"github.com/tendermint/starport/starport/pkg/cosmosprotoc"
 	"github.com/tendermint/starport/starport/pkg/cosmosver"
 	"github.com/tendermint/starport/starport/pkg/gomodulepath"
+	"github.com/tendermint/starport/starport/pkg/toolcache"
 	"github.com/tendermint/starport/starport/pkg/xos"
+	"github.com/tendermint/starport/starport/services"
 	"github.com/tendermint/starport/starport/templates/app"
 )
 
@@ -28,17 +29,18 @@ var (
 )
 
 // Init initializes a new app with name and given options.
-// path is the relative path to the scaffoled app.
+// the app is created in a directory under the path of the scaffolder,
+// path is the path to the scaffolded app.
 func (s *Scaffolder) Init(name string) (path string, err error) {
 	pathInfo, err := gomodulepath.Parse(name)
 	if err != nil {
 		return "", err
 	}
-	pwd, err := os.Getwd()
+	path = filepath.Join(s.path, pathInfo.Root)
+	absRoot, err := filepath.Abs(path)
 	if err != nil {
 		return "", err
 	}
-	absRoot := filepath.Join(pwd, pathInfo.Root)
 
 	// create the project
 	if err := s.generate(pathInfo, absRoot); err != nil {
@@ -56,10 +58,10 @@ func (s *Scaffolder) Init(name string) (path string, err error) {
 	}
 
 	// initialize git repository and perform the first commit
-	if err := initGit(pathInfo.Root); err != nil {
+	if err := initGit(absRoot); err != nil {
 		return "", err
 	}
-	return pathInfo.Root, nil
+	return path, nil
 }
 
 func (s *Scaffolder) generate(pathInfo gomodulepath.Path, absRoot string) error {
@@ -84,13 +86,6 @@ func (s *Scaffolder) protoc(projectPath, gomodPath string, version cosmosver.Maj
 		return nil
 	}
 
-	if err := cosmosprotoc.InstallDependencies(context.Background(), projectPath); err != nil {
-		if err == cosmosprotoc.ErrProtocNotInstalled {
-			return errors.ErrStarportRequiresProtoc
-		}
-		return err
-	}
-
 	confpath, err := conf.Locate(projectPath)
 	if err != nil {
 		return err
@@ -100,12 +95,25 @@ func (s *Scaffolder) protoc(projectPath, gomodPath string, version cosmosver.Maj
 		return err
 	}
 
+	backend, err := cosmosprotoc.NewBackend(conf.Build.Proto.Backend, toolcache.New(services.ToolsDir))
+	if err != nil {
+		return err
+	}
+
+	if err := backend.InstallDependencies(context.Background()); err != nil {
+		if err == cosmosprotoc.ErrProtocNotInstalled {
+			return errors.ErrStarportRequiresProtoc
+		}
+		return err
+	}
+
 	return cosmosprotoc.Generate(
 		context.Background(),
 		projectPath,
 		gomodPath,
 		filepath.Join(projectPath, conf.Build.Proto.Path),
 		xos.PrefixPathToList(conf.Build.Proto.ThirdPartyPaths, projectPath),
+		cosmosprotoc.WithBackend(backend),
 	)
 }
 
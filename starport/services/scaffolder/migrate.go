@@ -0,0 +1,488 @@
+package scaffolder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tendermint/starport/starport/pkg/cmdrunner"
+	"github.com/tendermint/starport/starport/pkg/cmdrunner/step"
+	"github.com/tendermint/starport/starport/pkg/cosmosver"
+	"github.com/tendermint/starport/starport/pkg/gomodulepath"
+	"github.com/tendermint/starport/starport/templates/typed"
+)
+
+const (
+	// launchpadBackupDir is the dir of an app where the sources of the Launchpad app are moved
+	// to when it's migrated to Stargate.
+	launchpadBackupDir = ".starport/launchpad"
+
+	// launchpadGenesisVersion is the version of the SDK to migrate a Launchpad genesis to.
+	launchpadGenesisVersion = "v0.40"
+)
+
+// launchpadPaths are the files and dirs of the Launchpad app template, they are moved to the
+// backup dir and replaced by the Stargate app template.
+var launchpadPaths = []string{
+	"app",
+	"cmd",
+	"x",
+	"vue",
+	"go.mod",
+	"go.sum",
+	"readme.md",
+	"Dockerfile",
+	".github",
+	".pi",
+	".gitignore",
+}
+
+// MigrateResult reports the changes made by MigrateStargate.
+type MigrateResult struct {
+	// Backup is the dir holding the sources of the Launchpad app.
+	Backup string
+
+	// Created holds the descriptions of the modules and types scaffolded in the Stargate app.
+	Created []string
+
+	// Custom holds the Launchpad files of the modules with custom logic, the logic needs to
+	// be ported to the Stargate app manually.
+	Custom []string
+
+	// Genesis is the path of the converted genesis, if a genesis was given.
+	Genesis string
+}
+
+// MigrateStargate migrates the Launchpad app to Stargate. the modules and types of the app are
+// scaffolded again with the Stargate templates, the proto definitions of the types are generated
+// from their Go structs. the sources of the Launchpad app are moved to a backup dir.
+// genesis is the optional path of a genesis exported by the Launchpad app, it's converted
+// with the migrate command of the SDK.
+// the sources of the Launchpad app are restored when the migration fails.
+func (s *Scaffolder) MigrateStargate(genesis string) (result MigrateResult, err error) {
+	version, err := s.version()
+	if err != nil {
+		return result, err
+	}
+	if version.Major() != cosmosver.Launchpad {
+		return result, errors.New("the app already uses Stargate")
+	}
+	path, err := gomodulepath.ParseAt(s.path)
+	if err != nil {
+		return result, err
+	}
+	appPath, err := filepath.Abs(s.path)
+	if err != nil {
+		return result, err
+	}
+	if genesis != "" {
+		if genesis, err = filepath.Abs(genesis); err != nil {
+			return result, err
+		}
+	}
+
+	// read the design of the Launchpad app before moving its sources
+	spec, err := launchpadSpec(appPath, path)
+	if err != nil {
+		return result, err
+	}
+	prefix, err := launchpadAddressPrefix(appPath)
+	if err != nil {
+		return result, err
+	}
+	config, err := ioutil.ReadFile(filepath.Join(appPath, "config.yml"))
+	if err != nil && !os.IsNotExist(err) {
+		return result, err
+	}
+
+	result.Backup = launchpadBackupDir
+	backupPath := filepath.Join(appPath, launchpadBackupDir)
+	if _, err := os.Stat(backupPath); err == nil {
+		return result, fmt.Errorf("%s already exists, remove it to migrate the app again", launchpadBackupDir)
+	}
+	existing, err := readDirNames(appPath)
+	if err != nil {
+		return result, err
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if rerr := restoreLaunchpad(appPath, backupPath, existing); rerr != nil {
+			err = fmt.Errorf("%s\nthe Launchpad app cannot be restored from %s: %s", err, launchpadBackupDir, rerr)
+		}
+	}()
+	if err := backupLaunchpad(appPath, backupPath); err != nil {
+		return result, err
+	}
+
+	// scaffold the Stargate app in place of the Launchpad one
+	stargate := New(s.path, AddressPrefix(prefix), SdkVersion(cosmosver.Stargate))
+	if err := stargate.generate(path, appPath); err != nil {
+		return result, err
+	}
+	if config != nil {
+		if err := ioutil.WriteFile(filepath.Join(appPath, "config.yml"), config, 0644); err != nil {
+			return result, err
+		}
+	}
+	applied, err := stargate.Apply(spec)
+	if err != nil {
+		return result, err
+	}
+	result.Created = applied.Created
+	if len(applied.Created) == 0 {
+		// Apply doesn't generate the code when there is nothing to scaffold
+		if err := stargate.finish(appPath, path.RawPath, cosmosver.Stargate); err != nil {
+			return result, err
+		}
+	}
+
+	if result.Custom, err = launchpadCustomFiles(appPath, backupPath, path, spec); err != nil {
+		return result, err
+	}
+
+	if genesis != "" {
+		if result.Genesis, err = migrateLaunchpadGenesis(appPath, path, genesis); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// launchpadSpec describes the modules and types of the Launchpad app at appPath.
+func launchpadSpec(appPath string, path gomodulepath.Path) (Spec, error) {
+	var spec Spec
+
+	wasm, err := isWasmImported(appPath)
+	if err != nil {
+		return spec, err
+	}
+	if wasm {
+		spec.Dependencies = append(spec.Dependencies, "wasm")
+	}
+
+	entries, err := ioutil.ReadDir(filepath.Join(appPath, moduleDir))
+	if err != nil {
+		return spec, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(appPath, moduleDir, entry.Name(), "module.go")); err != nil {
+			continue
+		}
+		types, err := launchpadTypes(filepath.Join(appPath, moduleDir, entry.Name(), "types"))
+		if err != nil {
+			return spec, err
+		}
+		module := ModuleSpec{Name: entry.Name(), Types: types}
+
+		// the app's module is created with the app, it goes first
+		if entry.Name() == path.Package {
+			spec.Modules = append([]ModuleSpec{module}, spec.Modules...)
+		} else {
+			spec.Modules = append(spec.Modules, module)
+		}
+	}
+	return spec, nil
+}
+
+// launchpadTypes finds the scaffolded types in the types package at typesPath.
+// a type is a struct X with a MsgCreateX message, its fields are the ones of the struct
+// except the creator and id.
+func launchpadTypes(typesPath string) ([]TypeSpec, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, typesPath, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	structs := make(map[string]*ast.StructType)
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			ast.Inspect(f, func(n ast.Node) bool {
+				spec, ok := n.(*ast.TypeSpec)
+				if !ok {
+					return true
+				}
+				if st, ok := spec.Type.(*ast.StructType); ok {
+					structs[spec.Name.Name] = st
+				}
+				return false
+			})
+		}
+	}
+
+	var types []TypeSpec
+	for name, st := range structs {
+		if _, ok := structs["MsgCreate"+name]; !ok || strings.HasPrefix(name, "Msg") {
+			continue
+		}
+		stype := TypeSpec{Name: lowerFirst(name)}
+		for _, field := range st.Fields.List {
+			if len(field.Names) == 0 {
+				continue
+			}
+			goName := field.Names[0].Name
+			if goName == "Creator" || goName == "ID" {
+				continue
+			}
+			fieldName := launchpadFieldName(field, goName)
+			ident, ok := field.Type.(*ast.Ident)
+			if !ok {
+				return nil, fmt.Errorf("the field %s of the type %s has an unsupported type", goName, name)
+			}
+			switch ident.Name {
+			case TypeString:
+				stype.Fields = append(stype.Fields, fieldName)
+			case TypeBool:
+				stype.Fields = append(stype.Fields, fieldName+":bool")
+			case TypeInt32:
+				stype.Fields = append(stype.Fields, fieldName+":int")
+			default:
+				return nil, fmt.Errorf("the field %s of the type %s has the unsupported type %s", goName, name, ident.Name)
+			}
+		}
+		types = append(types, stype)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types, nil
+}
+
+// launchpadFieldName returns the name of the field given to `starport type`, it's kept in the json tag.
+func launchpadFieldName(field *ast.Field, goName string) string {
+	if field.Tag != nil {
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err == nil {
+			if name := strings.Split(reflect.StructTag(tag).Get("json"), ",")[0]; name != "" {
+				return name
+			}
+		}
+	}
+	return lowerFirst(goName)
+}
+
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
+
+// launchpadAddressPrefix reads the address prefix of the Launchpad app.
+func launchpadAddressPrefix(appPath string) (string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filepath.Join(appPath, apppkg, "prefix.go"), nil, 0)
+	if err != nil {
+		return "", err
+	}
+	obj := f.Scope.Lookup("AccountAddressPrefix")
+	if obj == nil {
+		return "", errors.New("the address prefix of the app is not found")
+	}
+	spec, ok := obj.Decl.(*ast.ValueSpec)
+	if !ok || len(spec.Values) == 0 {
+		return "", errors.New("the address prefix of the app is not found")
+	}
+	lit, ok := spec.Values[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", errors.New("the address prefix of the app must be a string")
+	}
+	return strconv.Unquote(lit.Value)
+}
+
+// backupLaunchpad moves the sources of the Launchpad app to backupPath. the manifest of the
+// generated files is moved too since it records Launchpad templates.
+func backupLaunchpad(appPath, backupPath string) error {
+	if err := os.MkdirAll(backupPath, 0755); err != nil {
+		return err
+	}
+	paths := append([]string{generatedManifestPath, generatedBaseDir}, launchpadPaths...)
+	for _, path := range paths {
+		src := filepath.Join(appPath, path)
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			continue
+		}
+		dst := filepath.Join(backupPath, filepath.Base(path))
+		if err := os.Rename(src, dst); err != nil {
+			return err
+		}
+	}
+	// keep a copy of the config, it's restored in the Stargate app
+	config, err := ioutil.ReadFile(filepath.Join(appPath, "config.yml"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(backupPath, "config.yml"), config, 0644)
+}
+
+// restoreLaunchpad moves the sources of the Launchpad app back from backupPath and removes the
+// files of the Stargate app. existing holds the names of the files and dirs of the app before
+// its sources were moved, the other ones were created by the migration.
+func restoreLaunchpad(appPath, backupPath string, existing map[string]bool) error {
+	moved := make(map[string]bool)
+	for _, path := range launchpadPaths {
+		moved[path] = true
+	}
+	current, err := readDirNames(appPath)
+	if err != nil {
+		return err
+	}
+	for name := range current {
+		if name == filepath.Dir(launchpadBackupDir) || (existing[name] && !moved[name]) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(appPath, name)); err != nil {
+			return err
+		}
+	}
+
+	paths := append([]string{generatedManifestPath, generatedBaseDir}, launchpadPaths...)
+	for _, path := range paths {
+		dst := filepath.Join(appPath, path)
+		if err := os.RemoveAll(dst); err != nil {
+			return err
+		}
+		src := filepath.Join(backupPath, filepath.Base(path))
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			continue
+		}
+		if err := os.Rename(src, dst); err != nil {
+			return err
+		}
+	}
+	config, err := ioutil.ReadFile(filepath.Join(backupPath, "config.yml"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := ioutil.WriteFile(filepath.Join(appPath, "config.yml"), config, 0644); err != nil {
+			return err
+		}
+	}
+
+	if err := os.RemoveAll(backupPath); err != nil {
+		return err
+	}
+	if !existing[filepath.Dir(launchpadBackupDir)] {
+		return os.RemoveAll(filepath.Join(appPath, filepath.Dir(launchpadBackupDir)))
+	}
+	return nil
+}
+
+// readDirNames returns the names of the files and dirs in dir.
+func readDirNames(dir string) (map[string]bool, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, entry := range entries {
+		names[entry.Name()] = true
+	}
+	return names, nil
+}
+
+// launchpadCustomFiles lists the Go files of the Launchpad modules holding custom logic: the files
+// of the types edited since they were scaffolded and the files not created by Starport.
+// the files of the modules modified by the scaffolding of the types can't be told apart and
+// are not listed.
+func launchpadCustomFiles(appPath, backupPath string, path gomodulepath.Path, spec Spec) ([]string, error) {
+	generated := make(map[string][]byte)
+	for _, module := range spec.Modules {
+		for _, stype := range module.Types {
+			fields, err := parseFields(stype.Fields)
+			if err != nil {
+				return nil, err
+			}
+			files, err := renderTypeFiles(cosmosver.Launchpad, &typed.Options{
+				AppName:    path.Package,
+				ModulePath: path.RawPath,
+				ModuleName: module.Name,
+				OwnerName:  owner(path.RawPath),
+				TypeName:   stype.Name,
+				Fields:     fields,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for file, content := range files {
+				generated[file] = content
+			}
+		}
+	}
+
+	var custom []string
+	err := filepath.Walk(filepath.Join(backupPath, moduleDir), func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(file, ".go") {
+			return err
+		}
+		rel, err := filepath.Rel(backupPath, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if content, ok := generated[rel]; ok {
+			current, err := ioutil.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(current, content) {
+				custom = append(custom, rel)
+			}
+			return nil
+		}
+		// the files of the modules are replaced by their Stargate version
+		if _, err := os.Stat(filepath.Join(appPath, rel)); os.IsNotExist(err) {
+			custom = append(custom, rel)
+		}
+		return nil
+	})
+	return custom, err
+}
+
+// migrateLaunchpadGenesis converts the genesis exported by the Launchpad app to Stargate
+// with the migrate command of the SDK. the converted genesis is written next to genesis.
+func migrateLaunchpadGenesis(appPath string, path gomodulepath.Path, genesis string) (string, error) {
+	var (
+		out, errb bytes.Buffer
+		output    = strings.TrimSuffix(genesis, filepath.Ext(genesis)) + ".stargate.json"
+	)
+	err := cmdrunner.
+		New(
+			cmdrunner.DefaultStdout(&out),
+			cmdrunner.DefaultStderr(&errb),
+			cmdrunner.DefaultWorkdir(appPath),
+		).
+		Run(context.Background(),
+			step.New(
+				step.Exec(
+					"go",
+					"run",
+					"./cmd/"+path.Root+"d",
+					"migrate",
+					launchpadGenesisVersion,
+					genesis,
+				),
+			),
+		)
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", err, errb.String())
+	}
+	return output, ioutil.WriteFile(output, out.Bytes(), 0644)
+}
@@ -0,0 +1,115 @@
+package scaffolder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/starport/starport/pkg/cosmosver"
+	"github.com/tendermint/starport/starport/pkg/gomodulepath"
+)
+
+// scaffoldLaunchpadApp scaffolds a Launchpad app with the type post and a custom file in its
+// default module, it returns the path of the app.
+func scaffoldLaunchpadApp(t *testing.T) string {
+	appPath, err := New(t.TempDir(), SdkVersion(cosmosver.Launchpad), AddressPrefix("mars")).Init("github.com/foo/mars")
+	require.NoError(t, err)
+	_, err = New(appPath).AddType("", "post", "title", "likes:int", "published:bool")
+	require.NoError(t, err)
+	custom := []byte("package keeper\n\nfunc custom() {}\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(appPath, "x/mars/keeper/custom.go"), custom, 0644))
+	return appPath
+}
+
+// readTree returns the content of the files and the dirs under root by their path, the git
+// repository is skipped.
+func readTree(t *testing.T, root string) map[string]string {
+	tree := make(map[string]string)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			tree[rel+"/"] = ""
+			return nil
+		}
+		content, err := ioutil.ReadFile(path)
+		tree[rel] = string(content)
+		return err
+	})
+	require.NoError(t, err)
+	return tree
+}
+
+func TestLaunchpadSpec(t *testing.T) {
+	appPath := scaffoldLaunchpadApp(t)
+	path, err := gomodulepath.ParseAt(appPath)
+	require.NoError(t, err)
+
+	spec, err := launchpadSpec(appPath, path)
+	require.NoError(t, err)
+	require.Equal(t, Spec{
+		Modules: []ModuleSpec{
+			{Name: "mars", Types: []TypeSpec{{Name: "post", Fields: []string{"title", "likes:int", "published:bool"}}}},
+		},
+	}, spec)
+
+	prefix, err := launchpadAddressPrefix(appPath)
+	require.NoError(t, err)
+	require.Equal(t, "mars", prefix)
+}
+
+func TestMigrateStargateRestoresOnError(t *testing.T) {
+	appPath := scaffoldLaunchpadApp(t)
+
+	// the invalid config makes the generation of the proto code of the Stargate app fail,
+	// after the Launchpad sources are moved and the Stargate app is scaffolded.
+	config := []byte("accounts: {")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(appPath, "config.yml"), config, 0644))
+	before := readTree(t, appPath)
+
+	_, err := New(appPath).MigrateStargate("")
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "cannot be restored")
+
+	require.Equal(t, before, readTree(t, appPath))
+	version, err := cosmosver.Detect(appPath)
+	require.NoError(t, err)
+	require.Equal(t, cosmosver.Launchpad, version.Major())
+
+	// the migration can be run again.
+	_, err = New(appPath).MigrateStargate("")
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "already exists")
+}
+
+func TestRestoreLaunchpad(t *testing.T) {
+	appPath := scaffoldLaunchpadApp(t)
+	before := readTree(t, appPath)
+	existing, err := readDirNames(appPath)
+	require.NoError(t, err)
+
+	backupPath := filepath.Join(appPath, launchpadBackupDir)
+	require.NoError(t, backupLaunchpad(appPath, backupPath))
+	_, err = os.Stat(filepath.Join(appPath, "x"))
+	require.True(t, os.IsNotExist(err))
+
+	// files of the Stargate app, replacing Launchpad files or not.
+	for _, path := range []string{"go.mod", "x/mars/module.go", "proto/mars/post.proto", generatedManifestPath} {
+		require.NoError(t, os.MkdirAll(filepath.Join(appPath, filepath.Dir(path)), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(appPath, path), []byte("stargate"), 0644))
+	}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(appPath, "config.yml"), []byte("stargate"), 0644))
+
+	require.NoError(t, restoreLaunchpad(appPath, backupPath, existing))
+	require.Equal(t, before, readTree(t, appPath))
+}
@@ -1,6 +1,7 @@
 package scaffolder
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -29,33 +30,83 @@ const (
 	wasmVersionCommitStargate  = "f9015cba4793d03cf7a77d7253375b16ad3d3eef"
 )
 
+// moduleCreationOptions configures the creation of a module.
+type moduleCreationOptions struct {
+	moduleAccount bool
+	permissions   []string
+}
+
+// ModuleCreationOption configures the creation of a module.
+type ModuleCreationOption func(*moduleCreationOptions)
+
+// WithModuleAccount creates the module with a module account having permissions,
+// e.g.: minter, burner.
+func WithModuleAccount(permissions ...string) ModuleCreationOption {
+	return func(o *moduleCreationOptions) {
+		o.moduleAccount = true
+		o.permissions = permissions
+	}
+}
+
+// moduleAccountPermissions are the permissions a module account can have.
+var moduleAccountPermissions = []string{"minter", "burner", "staking"}
+
 // CreateModule creates a new empty module in the scaffolded app
-func (s *Scaffolder) CreateModule(moduleName string) error {
+func (s *Scaffolder) CreateModule(moduleName string, options ...ModuleCreationOption) (Changes, error) {
+	var changes Changes
 	version, err := s.version()
 	if err != nil {
-		return err
+		return changes, err
 	}
 	majorVersion := version.Major()
 	// Check if the module already exist
 	ok, err := ModuleExists(s.path, moduleName)
 	if err != nil {
-		return err
+		return changes, err
 	}
 	if ok {
-		return fmt.Errorf("the module %v already exists", moduleName)
+		return changes, fmt.Errorf("the module %v already exists", moduleName)
 	}
 	path, err := gomodulepath.ParseAt(s.path)
 	if err != nil {
-		return err
+		return changes, err
+	}
+	if err := s.createModule(path, majorVersion, &changes, moduleName, options...); err != nil {
+		return changes, err
+	}
+	appPath, err := s.appPath()
+	if err != nil {
+		return changes, err
+	}
+	return changes, s.finish(appPath, path.RawPath, majorVersion)
+}
+
+// createModule runs the generators to create a new module without generating
+// the proto code and formatting the source.
+func (s *Scaffolder) createModule(path gomodulepath.Path, majorVersion cosmosver.MajorVersion, changes *Changes, moduleName string, options ...ModuleCreationOption) error {
+	var creationOpts moduleCreationOptions
+	for _, o := range options {
+		o(&creationOpts)
+	}
+	if creationOpts.moduleAccount {
+		if majorVersion == cosmosver.Launchpad {
+			return errors.New("module accounts are only supported on Stargate")
+		}
+		if err := checkModuleAccountPermissions(creationOpts.permissions); err != nil {
+			return err
+		}
 	}
 
 	var (
+		err  error
 		g    *genny.Generator
 		opts = &module_create.CreateOptions{
-			ModuleName: moduleName,
-			ModulePath: path.RawPath,
-			AppName:    path.Package,
-			OwnerName:  owner(path.RawPath),
+			ModuleName:    moduleName,
+			ModulePath:    path.RawPath,
+			AppName:       path.Package,
+			OwnerName:     owner(path.RawPath),
+			ModuleAccount: creationOpts.moduleAccount,
+			Permissions:   creationOpts.permissions,
 		}
 	)
 	if majorVersion == cosmosver.Launchpad {
@@ -67,43 +118,73 @@ func (s *Scaffolder) CreateModule(moduleName string) error {
 	if err != nil {
 		return err
 	}
-	run := genny.WetRunner(context.Background())
-	run.With(g)
-	if err := run.Run(); err != nil {
-		return err
-	}
-	pwd, err := os.Getwd()
+	run, err := s.newRunner(changes)
 	if err != nil {
 		return err
 	}
+	run.With(g)
+	return run.Run()
+}
 
-	if err := s.protoc(pwd, path.RawPath, majorVersion); err != nil {
-		return err
+func checkModuleAccountPermissions(permissions []string) error {
+	existing := make(map[string]bool)
+	for _, permission := range permissions {
+		if existing[permission] {
+			return fmt.Errorf("the permission %s is duplicated", permission)
+		}
+		existing[permission] = true
+
+		var ok bool
+		for _, p := range moduleAccountPermissions {
+			if p == permission {
+				ok = true
+			}
+		}
+		if !ok {
+			return fmt.Errorf("the permission %s doesn't exist, use one of: %s", permission, strings.Join(moduleAccountPermissions, ", "))
+		}
 	}
-	return fmtProject(pwd)
+	return nil
 }
 
 // ImportModule imports specified module with name to the scaffolded app.
-func (s *Scaffolder) ImportModule(name string) error {
+func (s *Scaffolder) ImportModule(name string) (Changes, error) {
+	var changes Changes
 	version, err := s.version()
 	if err != nil {
-		return err
+		return changes, err
 	}
-	majorVersion := version.Major()
 	ok, err := isWasmImported(s.path)
 	if err != nil {
-		return err
+		return changes, err
 	}
 	if ok {
-		return errors.New("wasm is already imported")
+		return changes, errors.New("wasm is already imported")
 	}
+	if err := s.importModule(version, &changes, name); err != nil {
+		return changes, err
+	}
+	appPath, err := s.appPath()
+	if err != nil {
+		return changes, err
+	}
+	return changes, fmtProject(appPath)
+}
 
-	// import a specific version of ComsWasm
-	err = installWasm(version)
+// importModule installs and runs the generators to import the module with
+// name without formatting the source.
+func (s *Scaffolder) importModule(version cosmosver.Version, changes *Changes, name string) error {
+	majorVersion := version.Major()
+	appPath, err := s.appPath()
 	if err != nil {
 		return err
 	}
 
+	// import a specific version of ComsWasm
+	if err := installWasm(appPath, version); err != nil {
+		return err
+	}
+
 	path, err := gomodulepath.ParseAt(s.path)
 	if err != nil {
 		return err
@@ -127,16 +208,12 @@ func (s *Scaffolder) ImportModule(name string) error {
 	if err != nil {
 		return err
 	}
-	run := genny.WetRunner(context.Background())
-	run.With(g)
-	if err := run.Run(); err != nil {
-		return err
-	}
-	pwd, err := os.Getwd()
+	run, err := s.newRunner(changes)
 	if err != nil {
 		return err
 	}
-	return fmtProject(pwd)
+	run.With(g)
+	return run.Run()
 }
 
 func ModuleExists(appPath string, moduleName string) (bool, error) {
@@ -179,37 +256,33 @@ func isWasmImported(appPath string) (bool, error) {
 	return false, nil
 }
 
-func installWasm(version cosmosver.Version) error {
+func installWasm(appPath string, version cosmosver.Version) error {
+	var commit string
 	switch version {
 	case cosmosver.LaunchpadAny:
-		return cmdrunner.
-			New(
-				cmdrunner.DefaultStderr(os.Stderr),
-			).
-			Run(context.Background(),
-				step.New(
-					step.Exec(
-						"go",
-						"get",
-						wasmImport+"@"+wasmVersionCommitLaunchpad,
-					),
-				),
-			)
+		commit = wasmVersionCommitLaunchpad
 	case cosmosver.StargateZeroFourtyAndAbove:
-		return cmdrunner.
-			New(
-				cmdrunner.DefaultStderr(os.Stderr),
-			).
-			Run(context.Background(),
-				step.New(
-					step.Exec(
-						"go",
-						"get",
-						wasmImport+"@"+wasmVersionCommitStargate,
-					),
-				),
-			)
+		commit = wasmVersionCommitStargate
 	default:
 		return errors.New("version not supported")
 	}
+	errb := &bytes.Buffer{}
+	err := cmdrunner.
+		New(
+			cmdrunner.DefaultStderr(errb),
+			cmdrunner.DefaultWorkdir(appPath),
+		).
+		Run(context.Background(),
+			step.New(
+				step.Exec(
+					"go",
+					"get",
+					wasmImport+"@"+commit,
+				),
+			),
+		)
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, errb.String())
+	}
+	return nil
 }
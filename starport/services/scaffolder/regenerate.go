@@ -0,0 +1,162 @@
+package scaffolder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/tendermint/starport/starport/pkg/gitmerge"
+	"github.com/tendermint/starport/starport/pkg/gomodulepath"
+	"github.com/tendermint/starport/starport/templates/typed"
+)
+
+// RegenerateResult reports the files changed by Regenerate.
+type RegenerateResult struct {
+	// Updated holds the files that were not edited and are replaced by the new templates.
+	Updated []string
+
+	// Merged holds the edited files where the changes of the new templates are merged.
+	Merged []string
+
+	// Conflicts holds the edited files where the changes of the new templates conflict
+	// with the edits, they are left untouched.
+	Conflicts []string
+}
+
+// Regenerate renders the templates of the types recorded in the manifest of the app again,
+// so the fixes of the templates of the current Starport version get applied to the app.
+// the files that were not edited are replaced, the edits to the other files are kept with
+// a three-way merge and the files are left untouched when the merge conflicts.
+// only the files of a type recorded in the manifest are regenerated, the shared files the type
+// generator edits, like handler.go, query.proto, the genesis, app.go or the Vue router, are not.
+func (s *Scaffolder) Regenerate() (RegenerateResult, error) {
+	var result RegenerateResult
+
+	manifest, err := readGeneratedManifest(s.path)
+	if err != nil {
+		return result, err
+	}
+	if len(manifest.Types) == 0 {
+		return result, fmt.Errorf("no scaffolded types are recorded in %s", generatedManifestPath)
+	}
+	version, err := s.version()
+	if err != nil {
+		return result, err
+	}
+	majorVersion := version.Major()
+	path, err := gomodulepath.ParseAt(s.path)
+	if err != nil {
+		return result, err
+	}
+
+	for i, gtype := range manifest.Types {
+		tfields, err := parseFields(gtype.Fields)
+		if err != nil {
+			return result, err
+		}
+		files, err := renderTypeFiles(majorVersion, &typed.Options{
+			AppName:    path.Package,
+			ModulePath: path.RawPath,
+			ModuleName: gtype.Module,
+			OwnerName:  owner(path.RawPath),
+			TypeName:   gtype.Name,
+			Fields:     tfields,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		paths := make([]string, 0, len(files))
+		for file := range files {
+			paths = append(paths, file)
+		}
+		sort.Strings(paths)
+
+		if manifest.Types[i].Files == nil {
+			manifest.Types[i].Files = make(map[string]string)
+		}
+		for _, file := range paths {
+			changed, merged, err := s.regenerateFile(file, files[file], gtype.Files[file])
+			switch {
+			case err == gitmerge.ErrConflict:
+				result.Conflicts = append(result.Conflicts, file)
+				continue
+			case err != nil:
+				return result, err
+			case !changed:
+				continue
+			case merged:
+				result.Merged = append(result.Merged, file)
+			default:
+				result.Updated = append(result.Updated, file)
+			}
+			if err := writeGeneratedBase(s.path, file, files[file]); err != nil {
+				return result, err
+			}
+			manifest.Types[i].Files[file] = checksum(files[file])
+		}
+		manifest.Types[i].Version = templateVersion()
+	}
+
+	if err := writeGeneratedManifest(s.path, manifest); err != nil {
+		return result, err
+	}
+	if len(result.Updated) == 0 && len(result.Merged) == 0 {
+		return result, nil
+	}
+	pwd, err := filepath.Abs(s.path)
+	if err != nil {
+		return result, err
+	}
+	return result, s.finish(pwd, path.RawPath, majorVersion)
+}
+
+// regenerateFile replaces the file at path by its new generated content when it was not edited,
+// or merges the edits with the changes from its previous generated content.
+// the files added to the templates are created, the files removed from the app are left removed.
+func (s *Scaffolder) regenerateFile(path string, generated []byte, sum string) (changed, merged bool, err error) {
+	appPath := filepath.Join(s.path, path)
+	current, err := ioutil.ReadFile(appPath)
+	if os.IsNotExist(err) {
+		if sum != "" {
+			return false, false, nil
+		}
+		if err := os.MkdirAll(filepath.Dir(appPath), 0755); err != nil {
+			return false, false, err
+		}
+		return true, false, ioutil.WriteFile(appPath, generated, 0644)
+	}
+	if err != nil {
+		return false, false, err
+	}
+	if bytes.Equal(current, generated) {
+		return false, false, nil
+	}
+
+	// the file was not edited since it was generated
+	if checksum(current) == sum {
+		return true, false, ioutil.WriteFile(appPath, generated, 0644)
+	}
+
+	base, err := readGeneratedBase(s.path, path)
+	if os.IsNotExist(err) {
+		// without the base, the edits can't be told apart from the changes of the templates
+		return false, false, gitmerge.ErrConflict
+	}
+	if err != nil {
+		return false, false, err
+	}
+	if bytes.Equal(base, generated) {
+		// the templates didn't change
+		return false, false, nil
+	}
+	content, err := gitmerge.Merge(context.Background(), current, base, generated)
+	if err != nil {
+		return false, false, err
+	}
+	return true, true, ioutil.WriteFile(appPath, content, 0644)
+}
@@ -0,0 +1,135 @@
+package scaffolder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/starport/starport/pkg/gitmerge"
+)
+
+const regeneratedPath = "x/mars/post.txt"
+
+// generateFile writes the generated content of the file at regeneratedPath with its base,
+// the file of the app is written with current. it returns the checksum recorded for the file.
+func generateFile(t *testing.T, s *Scaffolder, generated, current string) string {
+	require.NoError(t, writeGeneratedBase(s.path, regeneratedPath, []byte(generated)))
+	path := filepath.Join(s.path, regeneratedPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, ioutil.WriteFile(path, []byte(current), 0644))
+	return checksum([]byte(generated))
+}
+
+func readRegenerated(t *testing.T, s *Scaffolder) string {
+	content, err := ioutil.ReadFile(filepath.Join(s.path, regeneratedPath))
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestRegenerateFile(t *testing.T) {
+	const base = "package mars\n\nfunc a() {}\n\nfunc b() {}\n\nfunc c() {}\n"
+
+	cases := []struct {
+		name      string
+		current   string
+		generated string
+		want      string
+		changed   bool
+		merged    bool
+		err       error
+	}{
+		{
+			name:      "unedited file",
+			current:   base,
+			generated: strings.Replace(base, "a()", "a(ctx)", 1),
+			want:      strings.Replace(base, "a()", "a(ctx)", 1),
+			changed:   true,
+		},
+		{
+			name:      "edited file",
+			current:   strings.Replace(base, "c()", "c(custom)", 1),
+			generated: strings.Replace(base, "a()", "a(ctx)", 1),
+			want:      strings.Replace(strings.Replace(base, "a()", "a(ctx)", 1), "c()", "c(custom)", 1),
+			changed:   true,
+			merged:    true,
+		},
+		{
+			name:      "conflicting edit",
+			current:   strings.Replace(base, "a()", "a(custom)", 1),
+			generated: strings.Replace(base, "a()", "a(ctx)", 1),
+			want:      strings.Replace(base, "a()", "a(custom)", 1),
+			err:       gitmerge.ErrConflict,
+		},
+		{
+			name:      "unchanged templates",
+			current:   strings.Replace(base, "c()", "c(custom)", 1),
+			generated: base,
+			want:      strings.Replace(base, "c()", "c(custom)", 1),
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(t.TempDir())
+			sum := generateFile(t, s, base, tt.current)
+
+			changed, merged, err := s.regenerateFile(regeneratedPath, []byte(tt.generated), sum)
+			require.Equal(t, tt.err, err)
+			require.Equal(t, tt.changed, changed)
+			require.Equal(t, tt.merged, merged)
+			require.Equal(t, tt.want, readRegenerated(t, s))
+		})
+	}
+}
+
+func TestRegenerateFileAddedAndRemoved(t *testing.T) {
+	s := New(t.TempDir())
+	generated := []byte("package mars\n")
+
+	// a file added to the templates is created.
+	changed, merged, err := s.regenerateFile(regeneratedPath, generated, "")
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.False(t, merged)
+	require.Equal(t, string(generated), readRegenerated(t, s))
+
+	// a generated file removed from the app is left removed.
+	require.NoError(t, os.Remove(filepath.Join(s.path, regeneratedPath)))
+	changed, _, err = s.regenerateFile(regeneratedPath, generated, checksum(generated))
+	require.NoError(t, err)
+	require.False(t, changed)
+	_, err = os.Stat(filepath.Join(s.path, regeneratedPath))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestRegenerateConflict(t *testing.T) {
+	s, _ := scaffoldStargateType(t, true, "title")
+	formatApp(t, s)
+
+	// the proto file was generated by older templates and edited on the line the templates changed.
+	const protoPath = "proto/mars/post.proto"
+	generated, err := readGeneratedBase(s.path, protoPath)
+	require.NoError(t, err)
+	older := strings.Replace(string(generated), "message MsgCreatePost {", "message MsgCreatePost { // older", 1)
+	edited := strings.Replace(string(generated), "message MsgCreatePost {", "message MsgCreatePost { // edited", 1)
+	require.NoError(t, writeGeneratedBase(s.path, protoPath, []byte(older)))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(s.path, protoPath), []byte(edited), 0644))
+	manifest, err := readGeneratedManifest(s.path)
+	require.NoError(t, err)
+	manifest.Types[0].Files[protoPath] = checksum([]byte(older))
+	require.NoError(t, writeGeneratedManifest(s.path, manifest))
+
+	result, err := s.Regenerate()
+	require.NoError(t, err)
+	require.Equal(t, RegenerateResult{Conflicts: []string{protoPath}}, result)
+
+	// the conflicting file and its record are left untouched.
+	content, err := ioutil.ReadFile(filepath.Join(s.path, protoPath))
+	require.NoError(t, err)
+	require.Equal(t, edited, string(content))
+	manifest, err = readGeneratedManifest(s.path)
+	require.NoError(t, err)
+	require.Equal(t, checksum([]byte(older)), manifest.Types[0].Files[protoPath])
+}
@@ -1,12 +1,19 @@
 // Package scaffolder initializes Starport apps and modifies existing ones
 // to add more features in a later time.
+// the operations only read and write the app at the path given to New, they don't depend
+// on the working directory and don't print, so they can be used by other programs.
 package scaffolder
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/gobuffalo/genny"
 	"github.com/tendermint/starport/starport/pkg/cmdrunner"
 	"github.com/tendermint/starport/starport/pkg/cmdrunner/step"
 
@@ -30,6 +37,71 @@ func New(path string, options ...Option) *Scaffolder {
 	}
 }
 
+// Changes reports the files created and modified by a scaffolding operation.
+// the paths are relative to the app, the code generated from the proto files isn't included.
+type Changes struct {
+	// Created holds the files created.
+	Created []string
+
+	// Modified holds the existing files modified.
+	Modified []string
+}
+
+func (c *Changes) record(path string, created bool) {
+	for _, p := range append(c.Created, c.Modified...) {
+		if p == path {
+			return
+		}
+	}
+	if created {
+		c.Created = append(c.Created, path)
+	} else {
+		c.Modified = append(c.Modified, path)
+	}
+}
+
+// appPath returns the absolute path of the app.
+func (s *Scaffolder) appPath() (string, error) {
+	return filepath.Abs(s.path)
+}
+
+// newRunner returns a runner writing the generated files to the app instead of the
+// working directory. the files written are recorded in changes.
+func (s *Scaffolder) newRunner(changes *Changes) (*genny.Runner, error) {
+	appPath, err := s.appPath()
+	if err != nil {
+		return nil, err
+	}
+	run := genny.WetRunner(context.Background())
+	run.Root = appPath
+	run.FileFn = func(f genny.File) (genny.File, error) {
+		path := f.Name()
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(appPath, path)
+		}
+		_, err := os.Stat(path)
+		created := os.IsNotExist(err)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return f, err
+		}
+		file, err := os.Create(path)
+		if err != nil {
+			return f, err
+		}
+		defer file.Close()
+		if _, err := io.Copy(file, f); err != nil {
+			return f, err
+		}
+		rel, err := filepath.Rel(appPath, path)
+		if err != nil {
+			return f, err
+		}
+		changes.record(filepath.ToSlash(rel), created)
+		return f, nil
+	}
+	return run, nil
+}
+
 func (s *Scaffolder) version() (cosmosver.Version, error) {
 	v, err := cosmosver.Detect(s.path)
 	if err != nil {
@@ -38,14 +110,24 @@ func (s *Scaffolder) version() (cosmosver.Version, error) {
 	return v, nil
 }
 
+// finish generates the proto code and formats the source of the app at pwd.
+// it is run once after all generators of a scaffolding operation.
+func (s *Scaffolder) finish(pwd, gomodPath string, version cosmosver.MajorVersion) error {
+	if err := s.protoc(pwd, gomodPath, version); err != nil {
+		return err
+	}
+	return fmtProject(pwd)
+}
+
 func owner(modulePath string) string {
 	return strings.Split(modulePath, "/")[1]
 }
 
 func fmtProject(path string) error {
-	return cmdrunner.
+	errb := &bytes.Buffer{}
+	err := cmdrunner.
 		New(
-			cmdrunner.DefaultStderr(os.Stderr),
+			cmdrunner.DefaultStderr(errb),
 			cmdrunner.DefaultWorkdir(path),
 		).
 		Run(context.Background(),
@@ -57,4 +139,8 @@ func fmtProject(path string) error {
 				),
 			),
 		)
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, errb.String())
+	}
+	return nil
 }
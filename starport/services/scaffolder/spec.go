@@ -0,0 +1,182 @@
+package scaffolder
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/goccy/go-yaml"
+	"github.com/tendermint/starport/starport/pkg/gomodulepath"
+)
+
+// Spec is a declarative description of the modules and types of an app.
+// it is used to scaffold all of them at once with Apply.
+type Spec struct {
+	// Dependencies holds a list of modules to import, e.g.: wasm.
+	Dependencies []string `yaml:"dependencies"`
+
+	// Modules holds the modules to create and the types they contain.
+	Modules []ModuleSpec `yaml:"modules"`
+}
+
+// ModuleSpec describes a module and its types.
+type ModuleSpec struct {
+	// Name of the module. it can be the app's default module.
+	Name string `yaml:"name"`
+
+	// Types holds the types to add to the module.
+	Types []TypeSpec `yaml:"types"`
+}
+
+// TypeSpec describes a type and its fields.
+type TypeSpec struct {
+	// Name of the type.
+	Name string `yaml:"name"`
+
+	// Fields of the type in the same format accepted by `starport type`,
+	// e.g.: title, likes:int.
+	Fields []string `yaml:"fields"`
+}
+
+// ParseSpec parses a spec from r.
+// the keys that aren't part of the spec are rejected, e.g.: messages and queries of modules,
+// there is no scaffolder for them yet.
+func ParseSpec(r io.Reader) (Spec, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Spec{}, err
+	}
+	var spec Spec
+	if err := yaml.Unmarshal(content, &spec); err != nil {
+		return Spec{}, err
+	}
+	// the spec is valid YAML, decoding it again only fails for unknown keys.
+	if err := yaml.UnmarshalWithOptions(content, &spec, yaml.DisallowUnknownField()); err != nil {
+		return Spec{}, fmt.Errorf("spec: %s\nonly dependencies, and modules with their types can be "+
+			"scaffolded from a spec, messages and queries are not supported yet", err)
+	}
+	return spec, spec.validate()
+}
+
+// ParseSpecFile parses the spec file at path.
+func ParseSpecFile(path string) (Spec, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Spec{}, err
+	}
+	defer file.Close()
+	return ParseSpec(file)
+}
+
+func (s Spec) validate() error {
+	for _, module := range s.Modules {
+		if module.Name == "" {
+			return errors.New("spec: module name is required")
+		}
+		for _, stype := range module.Types {
+			if stype.Name == "" {
+				return fmt.Errorf("spec: type name is required in module %s", module.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyResult reports what was scaffolded and what was skipped by Apply.
+type ApplyResult struct {
+	// Created holds the descriptions of the scaffolded items.
+	Created []string
+
+	// Skipped holds the descriptions of the items that already exist in the app.
+	Skipped []string
+
+	// Files holds the files created and modified.
+	Files Changes
+}
+
+// Apply scaffolds everything described in spec that doesn't exist in the app yet.
+// it can be run many times, the dependencies, modules and types already present are skipped.
+// proto code is generated and the source is formatted only once, after all the generators.
+func (s *Scaffolder) Apply(spec Spec) (ApplyResult, error) {
+	var (
+		result ApplyResult
+		types  []recordedType
+	)
+
+	version, err := s.version()
+	if err != nil {
+		return result, err
+	}
+	majorVersion := version.Major()
+	path, err := gomodulepath.ParseAt(s.path)
+	if err != nil {
+		return result, err
+	}
+
+	for _, dependency := range spec.Dependencies {
+		if dependency != "wasm" {
+			return result, fmt.Errorf("dependency %s cannot be imported", dependency)
+		}
+		ok, err := isWasmImported(s.path)
+		if err != nil {
+			return result, err
+		}
+		desc := "dependency " + dependency
+		if ok {
+			result.Skipped = append(result.Skipped, desc)
+			continue
+		}
+		if err := s.importModule(version, &result.Files, dependency); err != nil {
+			return result, err
+		}
+		result.Created = append(result.Created, desc)
+	}
+
+	for _, module := range spec.Modules {
+		ok, err := ModuleExists(s.path, module.Name)
+		if err != nil {
+			return result, err
+		}
+		desc := "module " + module.Name
+		if ok {
+			result.Skipped = append(result.Skipped, desc)
+		} else {
+			if err := s.createModule(path, majorVersion, &result.Files, module.Name); err != nil {
+				return result, err
+			}
+			result.Created = append(result.Created, desc)
+		}
+
+		for _, stype := range module.Types {
+			ok, err := isTypeCreated(s.path, module.Name, stype.Name)
+			if err != nil {
+				return result, err
+			}
+			desc := fmt.Sprintf("type %s/%s", module.Name, stype.Name)
+			if ok {
+				result.Skipped = append(result.Skipped, desc)
+				continue
+			}
+			opts, err := s.addType(path, majorVersion, &result.Files, module.Name, stype.Name, stype.Fields...)
+			if err != nil {
+				return result, err
+			}
+			types = append(types, recordedType{opts, stype.Fields})
+			result.Created = append(result.Created, desc)
+		}
+	}
+
+	if len(result.Created) == 0 {
+		return result, nil
+	}
+	appPath, err := s.appPath()
+	if err != nil {
+		return result, err
+	}
+	if err := s.finish(appPath, path.RawPath, majorVersion); err != nil {
+		return result, err
+	}
+	return result, s.recordTypes(majorVersion, types...)
+}
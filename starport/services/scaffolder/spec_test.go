@@ -0,0 +1,178 @@
+package scaffolder
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/starport/starport/pkg/cosmosver"
+)
+
+func TestParseSpec(t *testing.T) {
+	cases := []struct {
+		name string
+		yml  string
+		spec Spec
+		err  string
+	}{
+		{
+			name: "full",
+			yml: `
+dependencies:
+  - wasm
+modules:
+  - name: blog
+    types:
+      - name: post
+        fields: [title, likes:int]
+      - name: comment
+  - name: shop
+`,
+			spec: Spec{
+				Dependencies: []string{"wasm"},
+				Modules: []ModuleSpec{
+					{Name: "blog", Types: []TypeSpec{
+						{Name: "post", Fields: []string{"title", "likes:int"}},
+						{Name: "comment"},
+					}},
+					{Name: "shop"},
+				},
+			},
+		},
+		{
+			name: "empty",
+			yml:  "",
+		},
+		{
+			name: "messages are not supported",
+			yml: `
+modules:
+  - name: blog
+    messages:
+      - name: like-post
+`,
+			err: "messages and queries are not supported yet",
+		},
+		{
+			name: "queries are not supported",
+			yml: `
+modules:
+  - name: blog
+    queries:
+      - name: posts
+`,
+			err: `unknown field "queries"`,
+		},
+		{
+			name: "unknown type key",
+			yml: `
+modules:
+  - name: blog
+    types:
+      - name: post
+        field: [title]
+`,
+			err: `unknown field "field"`,
+		},
+		{
+			name: "invalid",
+			yml:  "modules: blog",
+			err:  "ArrayNode",
+		},
+		{
+			name: "module without name",
+			yml: `
+modules:
+  - types:
+      - name: post
+`,
+			err: "spec: module name is required",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpec(strings.NewReader(tt.yml))
+			if tt.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.spec, spec)
+		})
+	}
+}
+
+func TestSpecValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		spec Spec
+		err  string
+	}{
+		{
+			name: "valid",
+			spec: Spec{Modules: []ModuleSpec{{Name: "blog", Types: []TypeSpec{{Name: "post"}}}}},
+		},
+		{
+			name: "no modules",
+			spec: Spec{Dependencies: []string{"wasm"}},
+		},
+		{
+			name: "module without name",
+			spec: Spec{Modules: []ModuleSpec{{Types: []TypeSpec{{Name: "post"}}}}},
+			err:  "spec: module name is required",
+		},
+		{
+			name: "type without name",
+			spec: Spec{Modules: []ModuleSpec{{Name: "blog", Types: []TypeSpec{{Fields: []string{"title"}}}}}},
+			err:  "spec: type name is required in module blog",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.validate()
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestApplyTwice(t *testing.T) {
+	// Launchpad apps are scaffolded without generating proto code.
+	appPath, err := New(t.TempDir(), SdkVersion(cosmosver.Launchpad)).Init("github.com/foo/mars")
+	require.NoError(t, err)
+
+	spec := Spec{
+		Modules: []ModuleSpec{
+			{Name: "mars", Types: []TypeSpec{{Name: "user", Fields: []string{"email"}}}},
+			{Name: "blog", Types: []TypeSpec{{Name: "post", Fields: []string{"title", "likes:int"}}}},
+		},
+	}
+	all := []string{"module mars", "type mars/user", "module blog", "type blog/post"}
+
+	s := New(appPath)
+	result, err := s.Apply(spec)
+	require.NoError(t, err)
+	require.Equal(t, []string{"type mars/user", "module blog", "type blog/post"}, result.Created)
+	require.Equal(t, []string{"module mars"}, result.Skipped)
+	require.NotEmpty(t, result.Files.Created)
+
+	before, err := ioutil.ReadFile(filepath.Join(appPath, generatedManifestPath))
+	require.NoError(t, err)
+
+	result, err = s.Apply(spec)
+	require.NoError(t, err)
+	require.Empty(t, result.Created)
+	require.Equal(t, all, result.Skipped)
+	require.Empty(t, result.Files.Created)
+	require.Empty(t, result.Files.Modified)
+
+	after, err := ioutil.ReadFile(filepath.Join(appPath, generatedManifestPath))
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+}
@@ -1,11 +1,11 @@
 package scaffolder
 
 import (
-	"context"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -20,84 +20,111 @@ const (
 	TypeString = "string"
 	TypeBool   = "bool"
 	TypeInt32  = "int32"
+
+	// TypeStrings is a list of strings, its values are given as comma separated values in the CLI.
+	TypeStrings = "[]string"
+
+	typeReference = "ref"
+	modifierIndex = "index"
 )
 
 // AddType adds a new type stype to scaffolded app by using optional type fields.
-func (s *Scaffolder) AddType(moduleName string, stype string, fields ...string) error {
+func (s *Scaffolder) AddType(moduleName string, stype string, fields ...string) (Changes, error) {
+	var changes Changes
 	version, err := s.version()
 	if err != nil {
-		return err
+		return changes, err
 	}
 	majorVersion := version.Major()
 	path, err := gomodulepath.ParseAt(s.path)
 	if err != nil {
-		return err
+		return changes, err
+	}
+	opts, err := s.addType(path, majorVersion, &changes, moduleName, stype, fields...)
+	if err != nil {
+		return changes, err
 	}
+	appPath, err := s.appPath()
+	if err != nil {
+		return changes, err
+	}
+	if err := s.finish(appPath, path.RawPath, majorVersion); err != nil {
+		return changes, err
+	}
+	return changes, s.recordTypes(majorVersion, recordedType{opts, fields})
+}
 
+// addType runs the generators to add stype to the module without generating
+// the proto code and formatting the source. it returns the options of the generated type.
+func (s *Scaffolder) addType(path gomodulepath.Path, majorVersion cosmosver.MajorVersion, changes *Changes, moduleName, stype string, fields ...string) (*typed.Options, error) {
 	// If no module is provided, we add the type to the app's module
 	if moduleName == "" {
 		moduleName = path.Package
 	}
 	ok, err := ModuleExists(s.path, moduleName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !ok {
-		return fmt.Errorf("the module %s doesn't exist", moduleName)
+		return nil, fmt.Errorf("the module %s doesn't exist", moduleName)
 	}
 
 	// Ensure the type name is not a Go reserved name, it would generate an incorrect code
 	if isGoReservedWord(stype) {
-		return fmt.Errorf("%s can't be used as a type name", stype)
+		return nil, fmt.Errorf("%s can't be used as a type name", stype)
 	}
 
 	ok, err = isTypeCreated(s.path, moduleName, stype)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if ok {
-		return fmt.Errorf("%s type is already added", stype)
+		return nil, fmt.Errorf("%s type is already added", stype)
 	}
 
-	// Used to check duplicated field
-	existingFields := make(map[string]bool)
-
-	var tfields []typed.Field
-	for _, f := range fields {
-		fs := strings.Split(f, ":")
-		name := fs[0]
+	tfields, err := parseFields(fields)
+	if err != nil {
+		return nil, err
+	}
 
-		// Ensure the field name is not a Go reserved name, it would generate an incorrect code
-		if isGoReservedWord(name) {
-			return fmt.Errorf("%s can't be used as a field name", name)
+	// Ensure the referenced types exist in the module
+	for _, field := range tfields {
+		if field.Indexed && majorVersion == cosmosver.Launchpad {
+			return nil, fmt.Errorf("the field %s can't be indexed, indexes are only supported on Stargate", field.Name)
 		}
-
-		// Ensure the field is not duplicated
-		if _, exists := existingFields[name]; exists {
-			return fmt.Errorf("the field %s is duplicated", name)
+		if field.IsRepeated() && majorVersion == cosmosver.Launchpad {
+			return nil, fmt.Errorf("the field %s can't be a list, lists are only supported on Stargate", field.Name)
 		}
-		existingFields[name] = true
-
-		datatypeName, datatype := TypeString, TypeString
-		acceptedTypes := map[string]string{
-			"string": TypeString,
-			"bool":   TypeBool,
-			"int":    TypeInt32,
-		}
-		isTypeSpecified := len(fs) == 2
-		if isTypeSpecified {
-			if t, ok := acceptedTypes[fs[1]]; ok {
-				datatype = t
-				datatypeName = fs[1]
-			} else {
-				return fmt.Errorf("the field type %s doesn't exist", fs[1])
+		if field.Reference == "" {
+			continue
+		}
+		if majorVersion == cosmosver.Launchpad {
+			return nil, fmt.Errorf("the field %s can't reference a type, references are only supported on Stargate", field.Name)
+		}
+		if field.ReferenceModule != "" {
+			if err := s.checkModuleReference(moduleName, field); err != nil {
+				return nil, err
 			}
+			continue
+		}
+		ok, err := isTypeCreated(s.path, moduleName, field.Reference)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("the type %s referenced by the field %s doesn't exist in the module %s", field.Reference, field.Name, moduleName)
+		}
+
+		// the deletion behavior of the reference is added to the handler of the referenced type,
+		// it's checked before any file is written
+		handlerPath := fmt.Sprintf("x/%s/handler_%s.go", moduleName, field.Reference)
+		handler, err := ioutil.ReadFile(filepath.Join(s.path, handlerPath))
+		if err != nil {
+			return nil, err
+		}
+		if err := typed.CheckHandlerReference(handlerPath, string(handler), field.Reference); err != nil {
+			return nil, err
 		}
-		tfields = append(tfields, typed.Field{
-			Name:         name,
-			Datatype:     datatype,
-			DatatypeName: datatypeName,
-		})
 	}
 
 	var (
@@ -117,21 +144,130 @@ func (s *Scaffolder) AddType(moduleName string, stype string, fields ...string)
 		g, err = typed.NewStargate(opts)
 	}
 	if err != nil {
-		return err
+		return nil, err
+	}
+	run, err := s.newRunner(changes)
+	if err != nil {
+		return nil, err
 	}
-	run := genny.WetRunner(context.Background())
 	run.With(g)
-	if err := run.Run(); err != nil {
-		return err
+
+	// generate the views of the type when the app has a frontend
+	if _, err := os.Stat(filepath.Join(s.path, "vue")); err == nil && majorVersion == cosmosver.Stargate {
+		g, err := typed.NewVue(opts)
+		if err != nil {
+			return nil, err
+		}
+		run.With(g)
 	}
-	pwd, err := os.Getwd()
+	return opts, run.Run()
+}
+
+// checkModuleReference checks that the type of another module referenced by field exists.
+// the deletion of the types of other modules can't be restricted by the references to them.
+func (s *Scaffolder) checkModuleReference(moduleName string, field typed.Field) error {
+	if field.ReferenceModule == moduleName {
+		return fmt.Errorf("the field %s references a type of its own module, reference it as %s:ref:%s", field.Name, field.Name, field.Reference)
+	}
+	if field.OnDelete != "" {
+		return fmt.Errorf("the field %s references a type of the module %s, the deletion of the types of other modules can't be restricted or cascaded", field.Name, field.ReferenceModule)
+	}
+	ok, err := ModuleExists(s.path, field.ReferenceModule)
 	if err != nil {
 		return err
 	}
-	if err := s.protoc(pwd, path.RawPath, majorVersion); err != nil {
+	if !ok {
+		return fmt.Errorf("the module %s referenced by the field %s doesn't exist", field.ReferenceModule, field.Name)
+	}
+	ok, err = isTypeCreated(s.path, field.ReferenceModule, field.Reference)
+	if err != nil {
 		return err
 	}
-	return fmtProject(pwd)
+	if !ok {
+		return fmt.Errorf("the type %s referenced by the field %s doesn't exist in the module %s", field.Reference, field.Name, field.ReferenceModule)
+	}
+	return nil
+}
+
+// parseFields parses fields given in the name[:type][:index] format.
+// a field can reference another type with name:ref:type[:restrict|cascade][:index],
+// restrict or cascade configures what happens when the referenced object is deleted.
+// the types of other modules are referenced with name:ref:module.type[:index].
+// index maintains a secondary index to list the objects by the value of the field.
+func parseFields(fields []string) ([]typed.Field, error) {
+	// Used to check duplicated field
+	existingFields := make(map[string]bool)
+
+	var tfields []typed.Field
+	for _, f := range fields {
+		fs := strings.Split(f, ":")
+		name := fs[0]
+
+		// Ensure the field name is not a Go reserved name, it would generate an incorrect code
+		if isGoReservedWord(name) {
+			return nil, fmt.Errorf("%s can't be used as a field name", name)
+		}
+
+		// Ensure the field is not duplicated
+		if _, exists := existingFields[name]; exists {
+			return nil, fmt.Errorf("the field %s is duplicated", name)
+		}
+		existingFields[name] = true
+
+		field := typed.Field{
+			Name:         name,
+			Datatype:     TypeString,
+			DatatypeName: TypeString,
+		}
+		acceptedTypes := map[string]string{
+			"string":  TypeString,
+			"bool":    TypeBool,
+			"int":     TypeInt32,
+			"strings": TypeStrings,
+		}
+
+		var modifiers []string
+		switch {
+		case len(fs) == 1:
+		case fs[1] == typeReference:
+			// references hold the id of the referenced object
+			if len(fs) < 3 || fs[2] == "" {
+				return nil, fmt.Errorf("the field %s must specify the referenced type, e.g.: %s:ref:post", name, name)
+			}
+			field.Reference = fs[2]
+			field.OnDelete = typed.OnDeleteRestrict
+			// the types of other modules are referenced as module.type
+			if i := strings.Index(fs[2], "."); i != -1 {
+				field.ReferenceModule, field.Reference = fs[2][:i], fs[2][i+1:]
+				field.OnDelete = ""
+			}
+			modifiers = fs[3:]
+		default:
+			t, ok := acceptedTypes[fs[1]]
+			if !ok {
+				return nil, fmt.Errorf("the field type %s doesn't exist", fs[1])
+			}
+			field.Datatype = t
+			field.DatatypeName = fs[1]
+			modifiers = fs[2:]
+		}
+
+		for _, modifier := range modifiers {
+			switch {
+			case modifier == modifierIndex && field.IsRepeated():
+				return nil, fmt.Errorf("the field %s can't be indexed, lists can't be indexed", name)
+			case modifier == modifierIndex:
+				field.Indexed = true
+			case field.Reference != "" && (modifier == typed.OnDeleteRestrict || modifier == typed.OnDeleteCascade):
+				field.OnDelete = modifier
+			default:
+				return nil, fmt.Errorf("the field modifier %s doesn't exist for the field %s", modifier, name)
+			}
+		}
+
+		tfields = append(tfields, field)
+	}
+	return tfields, nil
 }
 
 func isTypeCreated(appPath, moduleName, typeName string) (isCreated bool, err error) {
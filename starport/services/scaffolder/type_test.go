@@ -0,0 +1,182 @@
+package scaffolder
+
+import (
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/starport/starport/pkg/cosmosver"
+)
+
+// readGo returns the content of the Go file at path in the app, it fails if the file isn't valid Go.
+func readGo(t *testing.T, s *Scaffolder, path string) string {
+	content, err := ioutil.ReadFile(filepath.Join(s.path, path))
+	require.NoError(t, err)
+	_, err = format.Source(content)
+	require.NoError(t, err, "%s is not valid Go", path)
+	return string(content)
+}
+
+// stubType declares the message created by the code generated from the proto of stype in module.
+func stubType(t *testing.T, s *Scaffolder, module, stype string) {
+	stub := fmt.Sprintf("package types\n\ntype MsgCreate%s struct{}\n", strings.Title(stype))
+	path := filepath.Join(s.path, "x", module, "types", stype+".pb.go")
+	require.NoError(t, ioutil.WriteFile(path, []byte(stub), 0644))
+}
+
+func TestAddTypeReference(t *testing.T) {
+	s, path := scaffoldStargateType(t, false, "title")
+
+	_, err := s.addType(path, cosmosver.Stargate, &Changes{}, "", "comment", "body", "post:ref:post:cascade")
+	require.NoError(t, err)
+	stubType(t, s, "mars", "comment")
+	_, err = s.addType(path, cosmosver.Stargate, &Changes{}, "", "reply", "body", "comment:ref:comment")
+	require.NoError(t, err)
+
+	// the comments are deleted through their own deletion, so the replies referencing them
+	// prevent deleting the post.
+	require.Contains(t, readGo(t, s, "x/mars/handler_post.go"), `for _, elem := range k.GetAllCommentByPost(ctx, id) {
+		if err := deleteComment(ctx, k, elem.Id); err != nil {
+			return err
+		}
+	}`)
+	comment := readGo(t, s, "x/mars/handler_comment.go")
+	require.Contains(t, comment, "func deleteComment(ctx sdk.Context, k keeper.Keeper, id string) error {")
+	require.Contains(t, comment, "if len(k.GetAllReplyByComment(ctx, id)) > 0 {")
+	require.Contains(t, comment, "if !k.HasPost(ctx, msg.Post) {")
+}
+
+func TestAddTypeModuleReference(t *testing.T) {
+	s, path := scaffoldStargateType(t, false, "title")
+	require.NoError(t, s.createModule(path, cosmosver.Stargate, &Changes{}, "blog"))
+
+	var changes Changes
+	_, err := s.addType(path, cosmosver.Stargate, &changes, "blog", "comment", "body", "postId:ref:mars.post", "authorId:ref:mars.post:index")
+	require.NoError(t, err)
+	require.Contains(t, changes.Created, "x/blog/types/expected_keepers.go")
+
+	require.Contains(t, readGo(t, s, "x/blog/types/expected_keepers.go"), `// MarsKeeper defines the expected mars keeper used by the module.
+type MarsKeeper interface {
+	HasPost(ctx sdk.Context, id string) bool
+}`)
+	keeper := readGo(t, s, "x/blog/keeper/keeper.go")
+	require.Contains(t, keeper, "marsKeeper types.MarsKeeper\n")
+	require.Contains(t, keeper, ", marsKeeper types.MarsKeeper) *Keeper {")
+	require.Contains(t, keeper, "marsKeeper: marsKeeper,")
+	require.Equal(t, 1, strings.Count(keeper, "func (k Keeper) HasMarsPost(ctx sdk.Context, id string) bool {"))
+	require.Contains(t, readGo(t, s, "app/app.go"), "keys[blogtypes.MemStoreKey],\napp.marsKeeper,\n")
+
+	handler := readGo(t, s, "x/blog/handler_comment.go")
+	require.Contains(t, handler, "if !k.HasMarsPost(ctx, msg.PostId) {")
+	require.Contains(t, handler, "if !k.HasMarsPost(ctx, msg.AuthorId) {")
+	// the deletion of the referenced type isn't restricted.
+	require.NotContains(t, readGo(t, s, "x/mars/handler_post.go"), "Comment")
+
+	// another reference to the module extends its expected keeper.
+	_, err = s.addType(path, cosmosver.Stargate, &Changes{}, "mars", "tag", "name")
+	require.NoError(t, err)
+	stubType(t, s, "mars", "tag")
+	_, err = s.addType(path, cosmosver.Stargate, &Changes{}, "blog", "label", "tagId:ref:mars.tag")
+	require.NoError(t, err)
+	require.Contains(t, readGo(t, s, "x/blog/types/expected_keepers.go"), `	HasPost(ctx sdk.Context, id string) bool
+	HasTag(ctx sdk.Context, id string) bool
+}`)
+	require.Equal(t, 1, strings.Count(readGo(t, s, "app/app.go"), "app.marsKeeper,\n"))
+}
+
+func TestAddTypeModuleReferenceErrors(t *testing.T) {
+	s, path := scaffoldStargateType(t, false, "title")
+	require.NoError(t, s.createModule(path, cosmosver.Stargate, &Changes{}, "blog"))
+	_, err := s.addType(path, cosmosver.Stargate, &Changes{}, "blog", "article", "title")
+	require.NoError(t, err)
+	stubType(t, s, "blog", "article")
+
+	cases := []struct {
+		module, field, err string
+	}{
+		{"blog", "postId:ref:mars.post:cascade", "the field postId references a type of the module mars, the deletion of the types of other modules can't be restricted or cascaded"},
+		{"blog", "postId:ref:forum.post", "the module forum referenced by the field postId doesn't exist"},
+		{"blog", "postId:ref:mars.comment", "the type comment referenced by the field postId doesn't exist in the module mars"},
+		{"mars", "postId:ref:mars.post", "the field postId references a type of its own module, reference it as postId:ref:post"},
+		// the keeper of the default module is created before the keepers of the other modules.
+		{"mars", "articleId:ref:blog.article", "app/app.go: app.blogKeeper must be created before the keeper of the module mars"},
+	}
+	for _, tt := range cases {
+		_, err := s.addType(path, cosmosver.Stargate, &Changes{}, tt.module, "comment", tt.field)
+		require.EqualError(t, err, tt.err)
+	}
+}
+
+func TestAddTypeReferenceWithoutPlaceholder(t *testing.T) {
+	s, path := scaffoldStargateType(t, false, "title")
+
+	// the placeholder is removed from the handler of the referenced type, e.g.: by an edit.
+	handlerPath := filepath.Join(s.path, "x/mars/handler_post.go")
+	handler, err := ioutil.ReadFile(handlerPath)
+	require.NoError(t, err)
+	var lines []string
+	for _, line := range strings.Split(string(handler), "\n") {
+		if !strings.Contains(line, "handler/delete") {
+			lines = append(lines, line)
+		}
+	}
+	require.NoError(t, ioutil.WriteFile(handlerPath, []byte(strings.Join(lines, "\n")), 0644))
+	before := readTree(t, s.path)
+
+	var changes Changes
+	_, err = s.addType(path, cosmosver.Stargate, &changes, "", "comment", "body", "post:ref:post")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "x/mars/handler_post.go doesn't contain the scaffolding placeholder for references, add \"// this line is used by starport scaffolding # handler/delete\" before the deletion in deletePost")
+
+	// nothing is written when the placeholder is missing.
+	require.Empty(t, changes.Created)
+	require.Empty(t, changes.Modified)
+	require.Equal(t, before, readTree(t, s.path))
+}
+
+func TestAddTypeMissingReference(t *testing.T) {
+	s, path := scaffoldStargateType(t, false, "title")
+
+	_, err := s.addType(path, cosmosver.Stargate, &Changes{}, "", "comment", "body", "author:ref:user")
+	require.EqualError(t, err, "the type user referenced by the field author doesn't exist in the module mars")
+}
+
+func TestAddTypeStrings(t *testing.T) {
+	s, _ := scaffoldStargateType(t, false, "title", "tags:strings")
+
+	read := func(path string) string {
+		content, err := ioutil.ReadFile(filepath.Join(s.path, path))
+		require.NoError(t, err)
+		if strings.HasSuffix(path, ".go") {
+			_, err := format.Source(content)
+			require.NoError(t, err, "%s is not valid Go", path)
+		}
+		return string(content)
+	}
+
+	proto := read("proto/mars/post.proto")
+	require.Contains(t, proto, "repeated string tags = 4;")
+	require.Contains(t, proto, "repeated string tags = 3;")
+	require.Contains(t, read("x/mars/types/messages_post.go"), "title string, tags []string)")
+
+	cli := read("x/mars/client/cli/txPost.go")
+	require.Contains(t, cli, `"strings"`)
+	require.NotContains(t, cli, `"strconv"`)
+	require.Contains(t, cli, `argsTags := strings.Split(args[1], ",")`)
+	require.Contains(t, cli, `argsTags := strings.Split(args[2], ",")`)
+
+	require.Contains(t, read("x/mars/client/rest/txPost.go"), "Tags []string `json:\"tags\"`")
+
+	require.Contains(t, read("vue/src/api/mars/post.js"), `{ name: "tags", type: "string", repeated: true, create: 3, update: 4 },`)
+	require.Contains(t, read("vue/src/components/mars/PostForm.vue"), `tags: item ? (item.tags || []).join(",") : "",`)
+	require.NotContains(t, read("vue/src/views/Index.vue"), "'tags'")
+}
+
+func TestAddTypeStringsIndex(t *testing.T) {
+	_, err := parseFields([]string{"tags:strings:index"})
+	require.EqualError(t, err, "the field tags can't be indexed, lists can't be indexed")
+}
@@ -0,0 +1,289 @@
+package scaffolder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	conf "github.com/tendermint/starport/starport/chainconf"
+	"github.com/tendermint/starport/starport/pkg/cmdrunner"
+	"github.com/tendermint/starport/starport/pkg/cmdrunner/step"
+	"github.com/tendermint/starport/starport/pkg/codemod"
+	"github.com/tendermint/starport/starport/pkg/cosmosver"
+	"github.com/tendermint/starport/starport/pkg/gomodule"
+	"github.com/tendermint/starport/starport/pkg/gomodulepath"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+const sdkModulePath = "github.com/cosmos/cosmos-sdk"
+
+// keeperCodec is the codec of the keepers of the scaffolded modules, the renamed codec methods
+// are only renamed on it, the other receivers of these method names may not be codecs.
+var keeperCodec = []string{"k.cdc"}
+
+// sdkUpgrade describes the changes to make to an app to upgrade it to a version of the SDK.
+type sdkUpgrade struct {
+	// Version of the SDK introducing the changes.
+	Version string
+
+	// Require holds the modules to require with the new version of the SDK.
+	Require []module.Version
+
+	// Rewrites applied to the Go source of the app.
+	Rewrites []codemod.Rewrite
+
+	// ManualSteps that can't be automated.
+	ManualSteps []string
+}
+
+// sdkUpgrades is the registry of the known breaking changes of the SDK since the version
+// used by the app templates, sorted by version.
+var sdkUpgrades = []sdkUpgrade{
+	{
+		Version: "v0.43.0",
+		Require: []module.Version{
+			{Path: "github.com/cosmos/ibc-go", Version: "v1.0.0"},
+		},
+		Rewrites: []codemod.Rewrite{
+			// IBC moved to its own module
+			codemod.RenameImport(sdkModulePath+"/x/ibc/applications", "github.com/cosmos/ibc-go/modules/apps"),
+			codemod.RenameImport(sdkModulePath+"/x/ibc/core", "github.com/cosmos/ibc-go/modules/core"),
+			codemod.RenameImport(sdkModulePath+"/x/ibc/light-clients", "github.com/cosmos/ibc-go/modules/light-clients"),
+			codemod.RenameImport(sdkModulePath+"/x/ibc/testing", "github.com/cosmos/ibc-go/testing"),
+
+			// codec names
+			codemod.RenameSelector(sdkModulePath+"/codec", "Marshaler", "Codec"),
+			codemod.RenameSelector(sdkModulePath+"/codec", "BinaryMarshaler", "BinaryCodec"),
+			codemod.RenameSelector(sdkModulePath+"/codec", "JSONMarshaler", "JSONCodec"),
+			codemod.RenameMethod(keeperCodec, "MarshalBinaryBare", "Marshal"),
+			codemod.RenameMethod(keeperCodec, "MustMarshalBinaryBare", "MustMarshal"),
+			codemod.RenameMethod(keeperCodec, "UnmarshalBinaryBare", "Unmarshal"),
+			codemod.RenameMethod(keeperCodec, "MustUnmarshalBinaryBare", "MustUnmarshal"),
+			codemod.RenameMethod(keeperCodec, "MarshalBinaryLengthPrefixed", "MarshalLengthPrefixed"),
+			codemod.RenameMethod(keeperCodec, "MustMarshalBinaryLengthPrefixed", "MustMarshalLengthPrefixed"),
+			codemod.RenameMethod(keeperCodec, "UnmarshalBinaryLengthPrefixed", "UnmarshalLengthPrefixed"),
+			codemod.RenameMethod(keeperCodec, "MustUnmarshalBinaryLengthPrefixed", "MustUnmarshalLengthPrefixed"),
+
+			// app.go wiring
+			codemod.AddArg(sdkModulePath+"/x/upgrade/keeper", "NewKeeper", 4, -1, "app.BaseApp"),
+			codemod.AddArg(sdkModulePath+"/types/module", "NewConfigurator", 2, 0, "app.appCodec"),
+		},
+		ManualSteps: []string{
+			"add a ConsensusVersion() uint64 method returning 1 to the AppModule of each module under x/",
+			"register the in-place store migrations of the upgraded modules in an upgrade handler in app/app.go",
+			"build the app and update the remaining calls to the renamed codec methods, only the calls on k.cdc are renamed",
+		},
+	},
+}
+
+// UpgradeResult reports the changes made by Upgrade.
+type UpgradeResult struct {
+	// From is the version of the SDK used by the app before the upgrade.
+	From string
+
+	// To is the version of the SDK used by the app after the upgrade.
+	To string
+
+	// Rewritten holds the Go files changed to follow the API changes of the SDK.
+	Rewritten []string
+
+	// ProtoUpdated holds the third party proto files updated with the ones of the SDK.
+	ProtoUpdated []string
+
+	// ManualSteps holds the changes to make manually to complete the upgrade.
+	ManualSteps []string
+}
+
+// Upgrade upgrades the app to the version to of the SDK. it updates go.mod, rewrites the Go
+// source for the known API changes of the SDK, updates the third party proto files copied
+// from the SDK and regenerates the proto code.
+func (s *Scaffolder) Upgrade(to string) (UpgradeResult, error) {
+	result := UpgradeResult{To: to}
+
+	if !semver.IsValid(to) {
+		return result, fmt.Errorf("%s is not a valid version of the SDK, e.g.: v0.43.0", to)
+	}
+	version, err := s.version()
+	if err != nil {
+		return result, err
+	}
+	majorVersion := version.Major()
+	if majorVersion == cosmosver.Launchpad {
+		return result, errors.New("upgrading is only supported for Stargate apps")
+	}
+	path, err := gomodulepath.ParseAt(s.path)
+	if err != nil {
+		return result, err
+	}
+	appPath, err := filepath.Abs(s.path)
+	if err != nil {
+		return result, err
+	}
+
+	gomod, err := gomodule.ParseAt(appPath)
+	if err != nil {
+		return result, err
+	}
+	required := gomodule.FilterRequire(gomod.Require, sdkModulePath)
+	if len(required) == 0 {
+		return result, fmt.Errorf("the app doesn't require %s", sdkModulePath)
+	}
+	result.From = required[0].Mod.Version
+	if semver.Compare(result.From, to) >= 0 {
+		return result, fmt.Errorf("the app already uses the version %s of the SDK", result.From)
+	}
+
+	// the upgrades of the versions between the current version and the new one, included
+	var upgrades []sdkUpgrade
+	for _, upgrade := range sdkUpgrades {
+		if semver.Compare(upgrade.Version, result.From) > 0 && semver.Compare(upgrade.Version, to) <= 0 {
+			upgrades = append(upgrades, upgrade)
+		}
+	}
+
+	// bump the requirements
+	if err := gomod.AddRequire(sdkModulePath, to); err != nil {
+		return result, err
+	}
+	var rewrites []codemod.Rewrite
+	for _, upgrade := range upgrades {
+		for _, req := range upgrade.Require {
+			if err := gomod.AddRequire(req.Path, req.Version); err != nil {
+				return result, err
+			}
+		}
+		rewrites = append(rewrites, upgrade.Rewrites...)
+		result.ManualSteps = append(result.ManualSteps, upgrade.ManualSteps...)
+	}
+	if err := writeGoMod(appPath, gomod); err != nil {
+		return result, err
+	}
+
+	// follow the API changes
+	if len(rewrites) > 0 {
+		changed, err := codemod.Apply(appPath, rewrites...)
+		if err != nil {
+			return result, err
+		}
+		for _, file := range changed {
+			rel, err := filepath.Rel(appPath, file)
+			if err != nil {
+				return result, err
+			}
+			result.Rewritten = append(result.Rewritten, rel)
+		}
+	}
+
+	if err := goModCommand(appPath, "download"); err != nil {
+		return result, err
+	}
+	if result.ProtoUpdated, err = updateThirdPartyProto(appPath); err != nil {
+		return result, err
+	}
+	if err := s.protoc(appPath, path.RawPath, majorVersion); err != nil {
+		return result, err
+	}
+	if err := goModCommand(appPath, "tidy"); err != nil {
+		return result, err
+	}
+	return result, fmtProject(appPath)
+}
+
+func writeGoMod(appPath string, gomod *modfile.File) error {
+	gomod.Cleanup()
+	content, err := gomod.Format()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(appPath, "go.mod"), content, 0644)
+}
+
+func goModCommand(appPath string, args ...string) error {
+	errb := &bytes.Buffer{}
+	err := cmdrunner.
+		New(
+			cmdrunner.DefaultStderr(errb),
+			cmdrunner.DefaultWorkdir(appPath),
+		).
+		Run(context.Background(),
+			step.New(step.Exec("go", append([]string{"mod"}, args...)...)),
+		)
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, errb.String())
+	}
+	return nil
+}
+
+// updateThirdPartyProto replaces the proto files under the third party proto paths of the app
+// by the ones of the same path in the SDK, and the other proto modules required by the app.
+// these files are often copied from the SDK and would shadow the proto files of its new version.
+func updateThirdPartyProto(appPath string) (updated []string, err error) {
+	confpath, err := conf.Locate(appPath)
+	if err != nil {
+		return nil, err
+	}
+	config, err := conf.ParseFile(confpath)
+	if err != nil {
+		return nil, err
+	}
+	gomod, err := gomodule.ParseAt(appPath)
+	if err != nil {
+		return nil, err
+	}
+	var sources []string
+	for _, required := range gomodule.FilterRequire(gomod.Require, sdkModulePath, "github.com/cosmos/ibc-go") {
+		srcPath, err := gomodule.LocatePath(required.Mod)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, filepath.Join(srcPath, "proto"), filepath.Join(srcPath, "third_party/proto"))
+	}
+
+	for _, thirdPartyPath := range config.Build.Proto.ThirdPartyPaths {
+		root := filepath.Join(appPath, thirdPartyPath)
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			continue
+		}
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || !strings.HasSuffix(path, ".proto") {
+				return err
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			for _, source := range sources {
+				content, err := ioutil.ReadFile(filepath.Join(source, rel))
+				if os.IsNotExist(err) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				current, err := ioutil.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				if string(current) != string(content) {
+					if err := ioutil.WriteFile(path, content, 0644); err != nil {
+						return err
+					}
+					updated = append(updated, filepath.Join(thirdPartyPath, rel))
+				}
+				break
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return updated, nil
+}
@@ -0,0 +1,130 @@
+package ante
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/gobuffalo/genny"
+	"github.com/gobuffalo/packr/v2"
+	"github.com/gobuffalo/plush"
+	"github.com/gobuffalo/plushgen"
+	"github.com/tendermint/starport/starport/pkg/xgenny"
+	"github.com/tendermint/starport/starport/templates/module"
+)
+
+// PathAnte is the path of the ante handler of the app.
+const PathAnte = "app/ante/ante.go"
+
+// these needs to be created in the compiler time, otherwise packr2 won't be
+// able to find boxes.
+var (
+	chainTemplate     = packr.New("ante/templates/stargate/chain", "./stargate/chain")
+	decoratorTemplate = packr.New("ante/templates/stargate/decorator", "./stargate/decorator")
+)
+
+// NewChainStargate returns the generator creating the ante handler of the app from the
+// default decorators of the SDK and setting it in app.go.
+func NewChainStargate(opts *Options) (*genny.Generator, error) {
+	g := genny.New()
+	g.RunFn(appModifyStargate(opts))
+	return g, box(chainTemplate, opts, g)
+}
+
+// NewDecoratorStargate returns the generator creating a custom decorator and adding it to
+// the ante handler of the app.
+func NewDecoratorStargate(opts *Options) (*genny.Generator, error) {
+	g := genny.New()
+	g.RunFn(anteModify(opts))
+	return g, box(decoratorTemplate, opts, g)
+}
+
+func box(b *packr.Box, opts *Options, g *genny.Generator) error {
+	if err := g.Box(b); err != nil {
+		return err
+	}
+	ctx := plush.NewContext()
+	ctx.Set("AppName", opts.AppName)
+	ctx.Set("ModulePath", opts.ModulePath)
+	ctx.Set("DecoratorName", opts.DecoratorName)
+	ctx.Set("title", strings.Title)
+	g.Transformer(plushgen.Transformer(ctx))
+	g.Transformer(genny.Replace("{{decoratorName}}", opts.DecoratorName))
+	return nil
+}
+
+// app.go modification on Stargate to set the ante handler of the app
+func appModifyStargate(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		path := module.PathAppGo
+		f, err := xgenny.Find(r, path)
+		if err != nil {
+			return err
+		}
+		content := f.String()
+
+		// replace the argument of SetAnteHandler
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, content, 0)
+		if err != nil {
+			return err
+		}
+		var handler ast.Expr
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if ok && sel.Sel.Name == "SetAnteHandler" && len(call.Args) == 1 {
+				handler = call.Args[0]
+				return false
+			}
+			return true
+		})
+		if handler == nil {
+			return errors.New("the ante handler is not set in app.go")
+		}
+		replacement := `appante.NewAnteHandler(
+			appante.HandlerOptions{
+				AccountKeeper:   app.AccountKeeper,
+				BankKeeper:      app.BankKeeper,
+				SigGasConsumer:  ante.DefaultSigVerificationGasConsumer,
+				SignModeHandler: encodingConfig.TxConfig.SignModeHandler(),
+			},
+		)`
+		start, end := fset.Position(handler.Pos()).Offset, fset.Position(handler.End()).Offset
+		content = content[:start] + replacement + content[end:]
+
+		// Import
+		template := `%[1]v
+		appante "%[2]v/app/ante"`
+		importReplacement := fmt.Sprintf(template, module.PlaceholderSgAppModuleImport, opts.ModulePath)
+		content = strings.Replace(content, module.PlaceholderSgAppModuleImport, importReplacement, 1)
+
+		newFile := genny.NewFileS(path, content)
+		return r.File(newFile)
+	}
+}
+
+// ante.go modification to add the custom decorator to the ante handler
+func anteModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		f, err := xgenny.Find(r, PathAnte)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(f.String(), placeholderAnteDecorator) {
+			return fmt.Errorf("%s doesn't contain the placeholder of the custom decorators", PathAnte)
+		}
+		template := `New%[2]vDecorator(),
+		%[1]v`
+		replacement := fmt.Sprintf(template, placeholderAnteDecorator, strings.Title(opts.DecoratorName))
+		content := strings.Replace(f.String(), placeholderAnteDecorator, replacement, 1)
+		newFile := genny.NewFileS(PathAnte, content)
+		return r.File(newFile)
+	}
+}
@@ -0,0 +1,13 @@
+package ante
+
+// Options ...
+type Options struct {
+	AppName       string
+	ModulePath    string
+	DecoratorName string
+}
+
+// Validate that options are usable
+func (opts *Options) Validate() error {
+	return nil
+}
@@ -0,0 +1,3 @@
+package ante
+
+const placeholderAnteDecorator = "// this line is used by starport scaffolding # ante/decorator"
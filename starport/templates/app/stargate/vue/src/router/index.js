@@ -1,6 +1,7 @@
 import Vue from "vue";
 import VueRouter from "vue-router";
 import Index from "../views/Index.vue";
+// this line is used by starport scaffolding # router/import
 
 Vue.use(VueRouter);
 
@@ -9,6 +10,13 @@ const routes = [
     path: "/",
     component: Index,
   },
+  // this line is used by starport scaffolding # router/route
+];
+
+// navigation lists the links to the views of the app.
+export const navigation = [
+  { path: "/", title: "Home" },
+  // this line is used by starport scaffolding # router/navigation
 ];
 
 const router = new VueRouter({
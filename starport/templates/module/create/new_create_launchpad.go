@@ -10,6 +10,7 @@ import (
 	"github.com/gobuffalo/plush"
 	"github.com/gobuffalo/plushgen"
 	"github.com/tendermint/starport/starport/pkg/cosmosver"
+	"github.com/tendermint/starport/starport/pkg/xgenny"
 )
 
 // New ...
@@ -34,7 +35,7 @@ func NewCreateLaunchpad(opts *CreateOptions) (*genny.Generator, error) {
 func appModifyLaunchpad(opts *CreateOptions) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := module.PathAppGo
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -10,6 +10,7 @@ import (
 	"github.com/gobuffalo/plush"
 	"github.com/gobuffalo/plushgen"
 	"github.com/tendermint/starport/starport/pkg/cosmosver"
+	"github.com/tendermint/starport/starport/pkg/xgenny"
 )
 
 // New ...
@@ -21,11 +22,18 @@ func NewCreateStargate(opts *CreateOptions) (*genny.Generator, error) {
 	if err := g.Box(templates[cosmosver.Stargate]); err != nil {
 		return g, err
 	}
+	if opts.ModuleAccount {
+		if err := g.Box(moduleAccountTemplate); err != nil {
+			return g, err
+		}
+	}
 	ctx := plush.NewContext()
 	ctx.Set("moduleName", opts.ModuleName)
 	ctx.Set("modulePath", opts.ModulePath)
 	ctx.Set("appName", opts.AppName)
 	ctx.Set("ownerName", opts.OwnerName)
+	ctx.Set("moduleAccount", opts.ModuleAccount)
+	ctx.Set("hasPermission", opts.HasPermission)
 	ctx.Set("title", strings.Title)
 
 	ctx.Set("nodash", func(s string) string {
@@ -41,7 +49,7 @@ func NewCreateStargate(opts *CreateOptions) (*genny.Generator, error) {
 func appModifyStargate(opts *CreateOptions) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := module.PathAppGo
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -73,15 +81,26 @@ func appModifyStargate(opts *CreateOptions) genny.RunFn {
 		content = strings.Replace(content, module.PlaceholderSgAppStoreKey, replacement, 1)
 
 		// Keeper definition
+		var keeperArgs string
+		if opts.ModuleAccount {
+			keeperArgs = `
+			app.AccountKeeper,
+			app.BankKeeper,`
+		}
 		template = `%[1]v
 		app.%[2]vKeeper = *%[2]vkeeper.NewKeeper(
 			appCodec,
 			keys[%[2]vtypes.StoreKey],
-			keys[%[2]vtypes.MemStoreKey],
+			keys[%[2]vtypes.MemStoreKey],%[3]v
 		)`
-		replacement = fmt.Sprintf(template, module.PlaceholderSgAppKeeperDefinition, opts.ModuleName)
+		replacement = fmt.Sprintf(template, module.PlaceholderSgAppKeeperDefinition, opts.ModuleName, keeperArgs)
 		content = strings.Replace(content, module.PlaceholderSgAppKeeperDefinition, replacement, 1)
 
+		// Module account permissions
+		if opts.ModuleAccount {
+			content = maccPermsModify(content, opts)
+		}
+
 		// App Module
 		template = `%[1]v
 		%[2]v.NewAppModule(appCodec, app.%[2]vKeeper),`
@@ -104,3 +123,26 @@ func appModifyStargate(opts *CreateOptions) genny.RunFn {
 		return r.File(newFile)
 	}
 }
+
+// maccPermsModify registers the module account and its permissions in maccPerms.
+// the apps scaffolded before the placeholder of maccPerms get the module account at its top.
+func maccPermsModify(content string, opts *CreateOptions) string {
+	permissions := "nil"
+	if len(opts.Permissions) > 0 {
+		var perms []string
+		for _, permission := range opts.Permissions {
+			perms = append(perms, "authtypes."+strings.Title(permission))
+		}
+		permissions = fmt.Sprintf("{%s}", strings.Join(perms, ", "))
+	}
+	entry := fmt.Sprintf("%vtypes.ModuleName: %v,", opts.ModuleName, permissions)
+
+	if strings.Contains(content, module.PlaceholderSgAppMaccPerms) {
+		template := `%[2]v
+		%[1]v`
+		replacement := fmt.Sprintf(template, module.PlaceholderSgAppMaccPerms, entry)
+		return strings.Replace(content, module.PlaceholderSgAppMaccPerms, replacement, 1)
+	}
+	const maccPermsDecl = "maccPerms = map[string][]string{"
+	return strings.Replace(content, maccPermsDecl, maccPermsDecl+"\n"+entry, 1)
+}
@@ -6,6 +6,22 @@ type CreateOptions struct {
 	ModulePath string
 	AppName    string
 	OwnerName  string
+
+	// ModuleAccount is true when the module has a module account.
+	ModuleAccount bool
+
+	// Permissions of the module account, e.g.: minter, burner.
+	Permissions []string
+}
+
+// HasPermission returns true when the module account has permission.
+func (opts *CreateOptions) HasPermission(permission string) bool {
+	for _, p := range opts.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
 }
 
 // Validate that options are usable
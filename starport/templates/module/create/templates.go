@@ -11,3 +11,6 @@ var templates = map[cosmosver.MajorVersion]*packr.Box{
 	cosmosver.Launchpad: packr.New("module/create/templates/launchpad", "./launchpad"),
 	cosmosver.Stargate:  packr.New("module/create/templates/stargate", "./stargate"),
 }
+
+// moduleAccountTemplate holds the files of the modules with a module account.
+var moduleAccountTemplate = packr.New("module/create/templates/moduleaccount", "./moduleaccount")
@@ -10,6 +10,7 @@ import (
 	"github.com/gobuffalo/packr/v2"
 	"github.com/gobuffalo/plush"
 	"github.com/gobuffalo/plushgen"
+	"github.com/tendermint/starport/starport/pkg/xgenny"
 )
 
 // New ...
@@ -33,7 +34,7 @@ func NewImportLaunchpad(opts *ImportOptions) (*genny.Generator, error) {
 func appModifyLaunchpad(opts *ImportOptions) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := module.PathAppGo
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -136,7 +137,7 @@ func appModifyLaunchpad(opts *ImportOptions) genny.RunFn {
 func exportModifyLaunchpad(opts *ImportOptions) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := "app/export.go"
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -199,7 +200,7 @@ func exportModifyLaunchpad(opts *ImportOptions) genny.RunFn {
 func cmdMainModifyLaunchpad(opts *ImportOptions) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("cmd/%[1]vcli/main.go", opts.AppName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
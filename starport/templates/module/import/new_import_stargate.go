@@ -10,6 +10,7 @@ import (
 	"github.com/gobuffalo/packr/v2"
 	"github.com/gobuffalo/plush"
 	"github.com/gobuffalo/plushgen"
+	"github.com/tendermint/starport/starport/pkg/xgenny"
 )
 
 // New ...
@@ -32,7 +33,7 @@ func NewImportStargate(opts *ImportOptions) (*genny.Generator, error) {
 func appModifyStargate(opts *ImportOptions) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := module.PathAppGo
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -156,7 +157,7 @@ func appModifyStargate(opts *ImportOptions) genny.RunFn {
 func rootModifyStargate(opts *ImportOptions) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := "cmd/" + opts.BinaryNamePrefix + "d/cmd/root.go"
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -27,6 +27,7 @@ const (
 	PlaceholderSgAppGovProposalHandlers = "// this line is used by starport scaffolding # stargate/app/govProposalHandlers"
 	PlaceholderSgAppGovProposalHandler  = "// this line is used by starport scaffolding # stargate/app/govProposalHandler"
 	PlaceholderSgAppNewArgument         = "// this line is used by starport scaffolding # stargate/app/newArgument"
+	PlaceholderSgAppMaccPerms           = "// this line is used by starport scaffolding # stargate/app/maccPerms"
 
 	// Placeholders in Stargate app.go for wasm
 	PlaceholderSgWasmAppEnabledProposals = "// this line is used by starport scaffolding # stargate/wasm/app/enabledProposals"
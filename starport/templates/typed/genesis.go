@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gobuffalo/genny"
+	"github.com/tendermint/starport/starport/pkg/xgenny"
 )
 
 func (t *typedStargate) genesisModify(opts *Options, g *genny.Generator) {
@@ -16,7 +17,7 @@ func (t *typedStargate) genesisModify(opts *Options, g *genny.Generator) {
 func (t *typedStargate) genesisProtoModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("proto/%s/genesis.proto", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -49,7 +50,7 @@ import "%[2]v/%[3]v.proto";`
 func (t *typedStargate) genesisTypesModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/types/genesis.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -88,19 +89,30 @@ for _, elem := range gs.%[3]vList {
 func (t *typedStargate) genesisModuleModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/genesis.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
 
+		content := f.String()
+		if !strings.Contains(content, `"strconv"`) {
+			content = strings.Replace(content, "import (", "import (\n\t\"strconv\"", 1)
+		}
+
 		templateModuleInit := `%[1]v
 // Set all the %[2]v
+%[2]vCount := int64(len(genState.%[3]vList))
 for _, elem := range genState.%[3]vList {
 	k.Set%[3]v(ctx, *elem)
+
+	// the ids of deleted %[2]v are not reused
+	if id, err := strconv.ParseInt(elem.Id, 10, 64); err == nil && id >= %[2]vCount {
+		%[2]vCount = id + 1
+	}
 }
 
 // Set %[2]v count
-k.Set%[3]vCount(ctx, int64(len(genState.%[3]vList)))
+k.Set%[3]vCount(ctx, %[2]vCount)
 `
 		replacementModuleInit := fmt.Sprintf(
 			templateModuleInit,
@@ -108,7 +120,7 @@ k.Set%[3]vCount(ctx, int64(len(genState.%[3]vList)))
 			opts.TypeName,
 			strings.Title(opts.TypeName),
 		)
-		content := strings.Replace(f.String(), placeholderGenesisModuleInit, replacementModuleInit, 1)
+		content = strings.Replace(content, placeholderGenesisModuleInit, replacementModuleInit, 1)
 
 		templateModuleExport := `%[1]v
 // Get all %[2]v
@@ -0,0 +1,69 @@
+package typed
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gobuffalo/genny"
+	"github.com/tendermint/starport/starport/pkg/xgenny"
+)
+
+// invariantsBase is the content of keeper/invariants.go for the modules created before
+// the invariants were scaffolded.
+const invariantsBase = `package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// RegisterInvariants registers the invariants of the module with the crisis module.
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	%[1]v
+}
+`
+
+func (t *typedStargate) invariantsModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		path := fmt.Sprintf("x/%s/keeper/invariants.go", opts.ModuleName)
+		var content string
+		f, err := xgenny.Find(r, path)
+		switch {
+		case os.IsNotExist(err):
+			content = fmt.Sprintf(invariantsBase, placeholderInvariantsRegister)
+			if err := t.moduleInvariantsModify(opts, r); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			content = f.String()
+		}
+
+		template := `%[1]v
+register%[2]vInvariants(ir, k)`
+		replacement := fmt.Sprintf(template, placeholderInvariantsRegister, strings.Title(opts.TypeName))
+		content = strings.Replace(content, placeholderInvariantsRegister, replacement, 1)
+		newFile := genny.NewFileS(path, content)
+		return r.File(newFile)
+	}
+}
+
+// moduleInvariantsModify registers the invariants of the keeper in module.go of the modules
+// created before the invariants were scaffolded.
+func (t *typedStargate) moduleInvariantsModify(opts *Options, r *genny.Runner) error {
+	path := fmt.Sprintf("x/%s/module.go", opts.ModuleName)
+	f, err := xgenny.Find(r, path)
+	if err != nil {
+		return err
+	}
+	const registration = "func (am AppModule) RegisterInvariants(_ sdk.InvariantRegistry) {}"
+	if !strings.Contains(f.String(), registration) {
+		return fmt.Errorf("%s doesn't register the invariants, call keeper.RegisterInvariants(ir, am.keeper) in RegisterInvariants", path)
+	}
+	content := strings.Replace(f.String(), registration, `func (am AppModule) RegisterInvariants(ir sdk.InvariantRegistry) {
+	keeper.RegisterInvariants(ir, am.keeper)
+}`, 1)
+	newFile := genny.NewFileS(path, content)
+	return r.File(newFile)
+}
@@ -0,0 +1,745 @@
+package typed
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gobuffalo/genny"
+	"github.com/tendermint/starport/starport/pkg/xgenny"
+)
+
+// NewFieldStargate returns the generator to add opts.Fields to the existing type opts.TypeName.
+// the sources of the type are modified in place, so the custom logic they contain is kept.
+func NewFieldStargate(opts *Options) (*genny.Generator, error) {
+	t := typedStargate{}
+	g := genny.New()
+	// the proto file is modified first, it fails if a field already exists
+	g.RunFn(t.fieldProtoModify(opts))
+	g.RunFn(t.fieldMessagesModify(opts))
+	g.RunFn(t.fieldKeeperModify(opts))
+	g.RunFn(t.fieldHandlerModify(opts))
+	g.RunFn(t.fieldClientCliTxModify(opts))
+	g.RunFn(t.fieldClientRestTxModify(opts))
+	g.RunFn(t.fieldFrontendModify(opts))
+	g.RunFn(t.fieldVueAPIModify(opts))
+	g.RunFn(t.fieldVueComponentsModify(opts))
+	return g, nil
+}
+
+func (t *typedStargate) fieldProtoModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		path := fmt.Sprintf("proto/%s/%s.proto", opts.ModuleName, opts.TypeName)
+		f, err := xgenny.Find(r, path)
+		if err != nil {
+			return err
+		}
+		content := f.String()
+		messages := []string{
+			strings.Title(opts.TypeName),
+			"MsgCreate" + strings.Title(opts.TypeName),
+			"MsgUpdate" + strings.Title(opts.TypeName),
+		}
+		for _, message := range messages {
+			for _, field := range opts.Fields {
+				content, err = protoAppendField(content, message, field)
+				if err != nil {
+					return fmt.Errorf("%s: %s", path, err)
+				}
+			}
+		}
+		newFile := genny.NewFileS(path, content)
+		return r.File(newFile)
+	}
+}
+
+func (t *typedStargate) fieldMessagesModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		path := fmt.Sprintf("x/%s/types/messages_%s.go", opts.ModuleName, opts.TypeName)
+		return modifyGoFile(r, path, func(fset *token.FileSet, file *ast.File, content string) ([]goEdit, error) {
+			var edits []goEdit
+			for _, name := range []string{"NewMsgCreate", "NewMsgUpdate"} {
+				name += strings.Title(opts.TypeName)
+				fn := findFunc(file, name)
+				if fn == nil {
+					return nil, fmt.Errorf("cannot find the function %s", name)
+				}
+				params := fn.Type.Params.List
+				lit := findCompositeLit(fn, name[len("New"):])
+				if len(params) == 0 || lit == nil {
+					return nil, fmt.Errorf("cannot find the message built by %s", name)
+				}
+				for _, field := range opts.Fields {
+					edits = append(edits,
+						insertAt(fset, params[len(params)-1].End(), fmt.Sprintf(", %s %s", field.Name, field.Datatype)),
+						appendElt(fset, content, lit, fmt.Sprintf("%s: %s", strings.Title(field.Name), field.Name)),
+					)
+				}
+			}
+			return edits, nil
+		})
+	}
+}
+
+func (t *typedStargate) fieldKeeperModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		path := fmt.Sprintf("x/%s/keeper/%s.go", opts.ModuleName, opts.TypeName)
+		return t.fieldFromMsgModify(r, path, "Create"+strings.Title(opts.TypeName), opts)
+	}
+}
+
+func (t *typedStargate) fieldHandlerModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		path := fmt.Sprintf("x/%s/handler_%s.go", opts.ModuleName, opts.TypeName)
+		return t.fieldFromMsgModify(r, path, "handleMsgUpdate"+strings.Title(opts.TypeName), opts)
+	}
+}
+
+// fieldFromMsgModify adds the fields to the type built from a message in funcName.
+func (t *typedStargate) fieldFromMsgModify(r *genny.Runner, path, funcName string, opts *Options) error {
+	return modifyGoFile(r, path, func(fset *token.FileSet, file *ast.File, content string) ([]goEdit, error) {
+		fn := findFunc(file, funcName)
+		if fn == nil {
+			return nil, fmt.Errorf("cannot find the function %s", funcName)
+		}
+		lit := findCompositeLit(fn, strings.Title(opts.TypeName))
+		if lit == nil {
+			return nil, fmt.Errorf("cannot find the %s built by %s", opts.TypeName, funcName)
+		}
+		var edits []goEdit
+		for _, field := range opts.Fields {
+			edits = append(edits, appendElt(fset, content, lit, fmt.Sprintf("%[1]v: msg.%[1]v", strings.Title(field.Name))))
+		}
+		return edits, nil
+	})
+}
+
+func (t *typedStargate) fieldClientCliTxModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		path := fmt.Sprintf("x/%s/client/cli/tx%s.go", opts.ModuleName, strings.Title(opts.TypeName))
+		return modifyGoFile(r, path, func(fset *token.FileSet, file *ast.File, content string) ([]goEdit, error) {
+			var edits []goEdit
+			for _, action := range []string{"Create", "Update"} {
+				name := "Cmd" + action + strings.Title(opts.TypeName)
+				fn := findFunc(file, name)
+				if fn == nil {
+					return nil, fmt.Errorf("cannot find the function %s", name)
+				}
+				cmdEdits, err := cliCommandEdits(fset, content, fn, "types.NewMsg"+action+strings.Title(opts.TypeName), opts.Fields)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s", name, err)
+				}
+				edits = append(edits, cmdEdits...)
+			}
+			for _, field := range opts.Fields {
+				if field.DatatypeName != "string" && !field.IsRepeated() {
+					edits = append(edits, addImport(fset, file, "strconv")...)
+					break
+				}
+			}
+			for _, field := range opts.Fields {
+				if field.IsRepeated() {
+					edits = append(edits, addImport(fset, file, "strings")...)
+					break
+				}
+			}
+			return edits, nil
+		})
+	}
+}
+
+// cliCommandEdits adds the fields to the args of the command created by fn.
+func cliCommandEdits(fset *token.FileSet, content string, fn *ast.FuncDecl, newMsg string, fields []Field) ([]goEdit, error) {
+	var (
+		edits    []goEdit
+		use      *ast.BasicLit
+		argCount *ast.BasicLit
+		body     *ast.BlockStmt
+	)
+	ast.Inspect(fn, func(n ast.Node) bool {
+		kv, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		switch key.Name {
+		case "Use":
+			use, _ = kv.Value.(*ast.BasicLit)
+		case "Args":
+			if call, ok := kv.Value.(*ast.CallExpr); ok && len(call.Args) == 1 {
+				argCount, _ = call.Args[0].(*ast.BasicLit)
+			}
+		case "RunE":
+			if lit, ok := kv.Value.(*ast.FuncLit); ok {
+				body = lit.Body
+			}
+		}
+		return true
+	})
+	if use == nil || argCount == nil || body == nil {
+		return nil, fmt.Errorf("cannot find the Use, Args and RunE of the command")
+	}
+	count, err := strconv.Atoi(argCount.Value)
+	if err != nil {
+		return nil, err
+	}
+	call := findCall(body, newMsg)
+	if call == nil || len(call.Args) == 0 {
+		return nil, fmt.Errorf("cannot find the call to %s", newMsg)
+	}
+	stmt := findAssign(body, "clientCtx")
+	if stmt == nil {
+		return nil, fmt.Errorf("cannot find the client context")
+	}
+
+	var (
+		usage, parsing string
+		args           []string
+	)
+	for i, field := range fields {
+		usage += fmt.Sprintf(" [%s]", field.Name)
+		switch {
+		case field.IsRepeated():
+			parsing += fmt.Sprintf("args%s := strings.Split(args[%d], \",\")\n", strings.Title(field.Name), count+i)
+		case field.DatatypeName == "string":
+			parsing += fmt.Sprintf("args%s := string(args[%d])\n", strings.Title(field.Name), count+i)
+		case field.DatatypeName == "int":
+			parsing += fmt.Sprintf("args%s, _ := strconv.ParseInt(args[%d], 10, 64)\n", strings.Title(field.Name), count+i)
+		default:
+			parsing += fmt.Sprintf("args%s, _ := strconv.Parse%s(args[%d])\n", strings.Title(field.Name), strings.Title(field.DatatypeName), count+i)
+		}
+		args = append(args, fmt.Sprintf("%s(args%s)", field.Datatype, strings.Title(field.Name)))
+	}
+	edits = append(edits,
+		insertAt(fset, use.End()-1, usage),
+		goEdit{
+			start: fset.Position(argCount.Pos()).Offset,
+			end:   fset.Position(argCount.End()).Offset,
+			text:  strconv.Itoa(count + len(fields)),
+		},
+		insertAt(fset, lineStart(fset, content, stmt.Pos()), parsing),
+		appendArgs(fset, call, args),
+	)
+	return edits, nil
+}
+
+func (t *typedStargate) fieldClientRestTxModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		path := fmt.Sprintf("x/%s/client/rest/tx%s.go", opts.ModuleName, strings.Title(opts.TypeName))
+		return modifyGoFile(r, path, func(fset *token.FileSet, file *ast.File, content string) ([]goEdit, error) {
+			var edits []goEdit
+			for _, action := range []string{"create", "update"} {
+				name := action + strings.Title(opts.TypeName) + "Request"
+				req := findStruct(file, name)
+				if req == nil {
+					return nil, fmt.Errorf("cannot find the struct %s", name)
+				}
+				for _, field := range opts.Fields {
+					// lists are sent as JSON arrays, the other values as strings
+					datatype := "string"
+					if field.IsRepeated() {
+						datatype = field.Datatype
+					}
+					edits = append(edits, appendStructField(fset, content, req, fmt.Sprintf("%s %s `json:\"%s\"`", strings.Title(field.Name), datatype, field.Name)))
+				}
+
+				name = action + strings.Title(opts.TypeName) + "Handler"
+				fn := findFunc(file, name)
+				if fn == nil {
+					return nil, fmt.Errorf("cannot find the function %s", name)
+				}
+				newMsg := "types.NewMsg" + strings.Title(action) + strings.Title(opts.TypeName)
+				call := findCall(fn.Body, newMsg)
+				stmt := findAssign(fn.Body, "msg")
+				if call == nil || len(call.Args) == 0 || stmt == nil {
+					return nil, fmt.Errorf("%s: cannot find the call to %s", name, newMsg)
+				}
+				var (
+					parsing string
+					args    []string
+				)
+				for _, field := range opts.Fields {
+					parsing += restParsing(field)
+					args = append(args, "parsed"+strings.Title(field.Name))
+				}
+				edits = append(edits,
+					insertAt(fset, lineStart(fset, content, stmt.Pos()), parsing),
+					appendArgs(fset, call, args),
+				)
+			}
+			return edits, nil
+		})
+	}
+}
+
+// restParsing returns the code parsing field from a REST request.
+func restParsing(field Field) string {
+	switch field.Datatype {
+	case "int32":
+		return fmt.Sprintf(`parsed%[1]v64, err := strconv.ParseInt(req.%[1]v, 10, 32)
+if err != nil {
+	rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	return
+}
+parsed%[1]v := int32(parsed%[1]v64)
+
+`, strings.Title(field.Name))
+	case "bool":
+		return fmt.Sprintf(`parsed%[1]v, err := strconv.ParseBool(req.%[1]v)
+if err != nil {
+	rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	return
+}
+
+`, strings.Title(field.Name))
+	default:
+		return fmt.Sprintf("parsed%[1]v := req.%[1]v\n\n", strings.Title(field.Name))
+	}
+}
+
+func (t *typedStargate) fieldFrontendModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		path := "vue/src/views/Index.vue"
+		f, err := xgenny.Find(r, path)
+		if os.IsNotExist(err) {
+			// Skip modification if the app doesn't contain front-end
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// the form uses the field numbers of the create message
+		protoPath := fmt.Sprintf("proto/%s/%s.proto", opts.ModuleName, opts.TypeName)
+		proto, err := xgenny.Find(r, protoPath)
+		if err != nil {
+			return err
+		}
+		var fields string
+		for _, field := range opts.Fields {
+			// the form only handles scalar fields
+			if field.IsRepeated() {
+				continue
+			}
+			number, err := protoFieldNumber(proto.String(), "MsgCreate"+strings.Title(opts.TypeName), field.Name)
+			if err != nil {
+				return fmt.Errorf("%s: %s", protoPath, err)
+			}
+			fields += fmt.Sprintf(`, ['%s', %d, '%s'] `, field.Name, number, field.Datatype)
+		}
+
+		form := regexp.MustCompile(fmt.Sprintf(`(<sp-type-form path="%s\.%s\.%s" type="%s" :fields="\[.*)(\]" />)`,
+			regexp.QuoteMeta(opts.OwnerName),
+			regexp.QuoteMeta(opts.AppName),
+			regexp.QuoteMeta(opts.ModuleName),
+			regexp.QuoteMeta(opts.TypeName),
+		))
+		content := form.ReplaceAllString(f.String(), "${1}"+strings.ReplaceAll(fields, "$", "$$")+"${2}")
+		newFile := genny.NewFileS(path, content)
+		return r.File(newFile)
+	}
+}
+
+func (t *typedStargate) fieldVueAPIModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		path := fmt.Sprintf("vue/src/api/%s/%s.js", opts.ModuleName, opts.TypeName)
+		f, err := xgenny.Find(r, path)
+		if os.IsNotExist(err) {
+			// Skip modification if the type was scaffolded without its front-end
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// the api encodes the messages with the field numbers of the proto file
+		protoPath := fmt.Sprintf("proto/%s/%s.proto", opts.ModuleName, opts.TypeName)
+		proto, err := xgenny.Find(r, protoPath)
+		if err != nil {
+			return err
+		}
+		var fields string
+		for _, field := range opts.Fields {
+			create, err := protoFieldNumber(proto.String(), "MsgCreate"+strings.Title(opts.TypeName), field.Name)
+			if err != nil {
+				return fmt.Errorf("%s: %s", protoPath, err)
+			}
+			update, err := protoFieldNumber(proto.String(), "MsgUpdate"+strings.Title(opts.TypeName), field.Name)
+			if err != nil {
+				return fmt.Errorf("%s: %s", protoPath, err)
+			}
+			var repeated string
+			if field.IsRepeated() {
+				repeated = "repeated: true, "
+			}
+			fields += fmt.Sprintf("\n  { name: \"%s\", type: \"%s\", %screate: %d, update: %d },", field.Name, field.ScalarType(), repeated, create, update)
+		}
+
+		list := regexp.MustCompile(`(?s)(export const fields = \[.*?)(\n\];)`)
+		if !list.MatchString(f.String()) {
+			return fmt.Errorf("%s: cannot find the list of the fields", path)
+		}
+		content := list.ReplaceAllString(f.String(), "${1}"+strings.ReplaceAll(fields, "$", "$$")+"${2}")
+		newFile := genny.NewFileS(path, content)
+		return r.File(newFile)
+	}
+}
+
+func (t *typedStargate) fieldVueComponentsModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		var form, values, header, cells, detail string
+		for _, field := range opts.Fields {
+			input := fmt.Sprintf(`<input v-model="values.%s" type="text" />`, field.Name)
+			value := `""`
+			switch field.Datatype {
+			case "bool":
+				input = fmt.Sprintf(`<input v-model="values.%s" type="checkbox" />`, field.Name)
+				value = "false"
+			case "int32":
+				input = fmt.Sprintf(`<input v-model.number="values.%s" type="number" />`, field.Name)
+				value = "0"
+			}
+			form += fmt.Sprintf("\n    <label>\n      %s\n      %s\n    </label>", field.Name, input)
+			item := "item." + field.Name
+			if field.IsRepeated() {
+				item = fmt.Sprintf(`(item.%s || []).join(",")`, field.Name)
+			}
+			values += fmt.Sprintf("\n    %s: item ? %s : %s,", field.Name, item, value)
+			header += fmt.Sprintf("\n        <th>%s</th>", field.Name)
+			cells += fmt.Sprintf("\n        <td>{{ item.%s }}</td>", field.Name)
+			detail += fmt.Sprintf("\n    <dt>%[1]s</dt>\n    <dd>{{ item.%[1]s }}</dd>", field.Name)
+		}
+
+		dir := fmt.Sprintf("vue/src/components/%s/%s", opts.ModuleName, strings.Title(opts.TypeName))
+		components := []struct {
+			path  string
+			edits []vueEdit
+		}{
+			{dir + "Form.vue", []vueEdit{
+				{`(?s)(<form .*?)(\n    <button type="submit")`, form},
+				{`(?s)(function initial\(item\) \{\n  return \{.*?)(\n  \};)`, values},
+			}},
+			{dir + "List.vue", []vueEdit{
+				{`(?s)(<th>id</th>.*?)(\n        <th>creator</th>)`, header},
+				{`(?s)(<td>\{\{ item\.id \}\}</td>.*?)(\n        <td>\{\{ item\.creator \}\}</td>)`, cells},
+			}},
+			{dir + "Detail.vue", []vueEdit{
+				{`(?s)(<dl class="sp-detail">.*?)(\n  </dl>)`, detail},
+			}},
+		}
+		for _, component := range components {
+			path := component.path
+			f, err := xgenny.Find(r, path)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			content := f.String()
+			for _, edit := range component.edits {
+				re := regexp.MustCompile(edit.pattern)
+				if !re.MatchString(content) {
+					return fmt.Errorf("%s: cannot find where to add the fields", path)
+				}
+				content = re.ReplaceAllString(content, "${1}"+strings.ReplaceAll(edit.text, "$", "$$")+"${2}")
+			}
+			if strings.HasSuffix(path, "List.vue") {
+				// the placeholder row of an empty list spans the new columns
+				colspan := regexp.MustCompile(`:colspan="(\d+)"`)
+				content = colspan.ReplaceAllStringFunc(content, func(s string) string {
+					n, _ := strconv.Atoi(colspan.FindStringSubmatch(s)[1])
+					return fmt.Sprintf(`:colspan="%d"`, n+len(opts.Fields))
+				})
+			}
+			if err := r.File(genny.NewFileS(path, content)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// vueEdit inserts text at the end of the first group matched by pattern,
+// before the second one.
+type vueEdit struct {
+	pattern, text string
+}
+
+var (
+	protoFieldNumberRe = regexp.MustCompile(`=\s*(\d+)\s*[;\[]`)
+	protoReservedRe    = regexp.MustCompile(`reserved\s+([^;]+);`)
+)
+
+// protoMessageBody returns the offsets of the body of message in content,
+// end is the offset of the closing brace.
+func protoMessageBody(content, message string) (start, end int, err error) {
+	re := regexp.MustCompile(fmt.Sprintf(`message\s+%s\s*\{`, regexp.QuoteMeta(message)))
+	loc := re.FindStringIndex(content)
+	if loc == nil {
+		return 0, 0, fmt.Errorf("cannot find the message %s", message)
+	}
+	depth := 1
+	for i := loc[1]; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return loc[1], i, nil
+			}
+		}
+	}
+	return 0, 0, fmt.Errorf("the message %s is not closed", message)
+}
+
+// protoAppendField appends field to message. the field number follows the highest
+// field number used or reserved in the message, so numbers of the existing fields
+// and the removed ones are never reused.
+func protoAppendField(content, message string, field Field) (string, error) {
+	start, end, err := protoMessageBody(content, message)
+	if err != nil {
+		return "", err
+	}
+	body := content[start:end]
+	if regexp.MustCompile(fmt.Sprintf(`\s%s\s*=`, regexp.QuoteMeta(field.Name))).MatchString(body) {
+		return "", fmt.Errorf("the field %s already exists in the message %s", field.Name, message)
+	}
+
+	var max int
+	for _, match := range protoFieldNumberRe.FindAllStringSubmatch(body, -1) {
+		if n, _ := strconv.Atoi(match[1]); n > max {
+			max = n
+		}
+	}
+	for _, match := range protoReservedRe.FindAllStringSubmatch(body, -1) {
+		// reserved ranges are given as "9 to 11" or "9 to max"
+		for _, number := range regexp.MustCompile(`\d+|max`).FindAllString(match[1], -1) {
+			if number == "max" {
+				return "", fmt.Errorf("the message %s reserves all the field numbers", message)
+			}
+			if n, _ := strconv.Atoi(number); n > max {
+				max = n
+			}
+		}
+	}
+
+	line := fmt.Sprintf("  %s %s = %d;\n", field.ProtoType(), field.Name, max+1)
+	return content[:end] + line + content[end:], nil
+}
+
+// protoFieldNumber returns the number of the field name in message.
+func protoFieldNumber(content, message, name string) (int, error) {
+	start, end, err := protoMessageBody(content, message)
+	if err != nil {
+		return 0, err
+	}
+	re := regexp.MustCompile(fmt.Sprintf(`\s%s\s*=\s*(\d+)`, regexp.QuoteMeta(name)))
+	match := re.FindStringSubmatch(content[start:end])
+	if match == nil {
+		return 0, fmt.Errorf("cannot find the field %s in the message %s", name, message)
+	}
+	return strconv.Atoi(match[1])
+}
+
+// goEdit replaces the source between the start and end offsets with text.
+type goEdit struct {
+	start, end int
+	text       string
+}
+
+// modifyGoFile applies the edits returned by edit to the Go source file at path.
+// the source is edited as text so its layout and comments are kept, it is formatted
+// once the scaffolding is done.
+func modifyGoFile(r *genny.Runner, path string, edit func(*token.FileSet, *ast.File, string) ([]goEdit, error)) error {
+	f, err := xgenny.Find(r, path)
+	if err != nil {
+		return err
+	}
+	content := f.String()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, content, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	edits, err := edit(fset, file, content)
+	if err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+
+	// apply the edits from the end of the file so offsets stay valid,
+	// edits at the same offset keep their order
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for i := len(edits) - 1; i > 0; i-- {
+		if edits[i].start == edits[i-1].start {
+			edits[i-1].text += edits[i].text
+			edits = append(edits[:i], edits[i+1:]...)
+		}
+	}
+	for _, e := range edits {
+		content = content[:e.start] + e.text + content[e.end:]
+	}
+	newFile := genny.NewFileS(path, content)
+	return r.File(newFile)
+}
+
+func insertAt(fset *token.FileSet, pos token.Pos, text string) goEdit {
+	offset := fset.Position(pos).Offset
+	return goEdit{start: offset, end: offset, text: text}
+}
+
+// lineStart returns the position of the start of the line of pos.
+func lineStart(fset *token.FileSet, content string, pos token.Pos) token.Pos {
+	offset := fset.Position(pos).Offset
+	return pos - token.Pos(offset-(strings.LastIndex(content[:offset], "\n")+1))
+}
+
+// appendElt appends the element elt to the composite literal lit.
+func appendElt(fset *token.FileSet, content string, lit *ast.CompositeLit, elt string) goEdit {
+	if fset.Position(lit.Lbrace).Line == fset.Position(lit.Rbrace).Line {
+		if len(lit.Elts) > 0 {
+			elt = ", " + elt
+		}
+		return insertAt(fset, lit.Rbrace, elt)
+	}
+	return insertAt(fset, lineStart(fset, content, lit.Rbrace), elt+",\n")
+}
+
+// appendStructField appends the field declaration decl to st.
+func appendStructField(fset *token.FileSet, content string, st *ast.StructType, decl string) goEdit {
+	if fields := st.Fields.List; len(fields) > 0 {
+		return insertAt(fset, fields[len(fields)-1].End(), "\n"+decl)
+	}
+	return insertAt(fset, lineStart(fset, content, st.Fields.Closing), decl+"\n")
+}
+
+// appendArgs appends args to the arguments of call, one per line
+// if the arguments of call are on several lines.
+func appendArgs(fset *token.FileSet, call *ast.CallExpr, args []string) goEdit {
+	sep := ", "
+	if fset.Position(call.Lparen).Line != fset.Position(call.Rparen).Line {
+		sep = ",\n"
+	}
+	return insertAt(fset, call.Args[len(call.Args)-1].End(), sep+strings.Join(args, sep))
+}
+
+// addImport returns the edits to import path in file if it's not imported yet.
+func addImport(fset *token.FileSet, file *ast.File, path string) []goEdit {
+	return addNamedImport(fset, file, "", path)
+}
+
+// addNamedImport returns the edits to import path with the name in file if it's not imported yet.
+func addNamedImport(fset *token.FileSet, file *ast.File, name, path string) []goEdit {
+	for _, spec := range file.Imports {
+		if spec.Path.Value == strconv.Quote(path) {
+			return nil
+		}
+	}
+	spec := strconv.Quote(path)
+	if name != "" {
+		spec = name + " " + spec
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT || !gen.Lparen.IsValid() {
+			continue
+		}
+		return []goEdit{insertAt(fset, gen.Lparen+1, "\n"+spec)}
+	}
+	return []goEdit{insertAt(fset, file.Name.End(), "\n\nimport "+spec)}
+}
+
+func findFunc(file *ast.File, name string) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+func findStruct(file *ast.File, name string) *ast.StructType {
+	var st *ast.StructType
+	ast.Inspect(file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok && spec.Name.Name == name {
+			st, _ = spec.Type.(*ast.StructType)
+		}
+		return st == nil
+	})
+	return st
+}
+
+// findCompositeLit returns the first composite literal of the type name in node,
+// the type can be qualified by a package.
+func findCompositeLit(node ast.Node, name string) *ast.CompositeLit {
+	var lit *ast.CompositeLit
+	ast.Inspect(node, func(n ast.Node) bool {
+		if l, ok := n.(*ast.CompositeLit); ok {
+			switch t := l.Type.(type) {
+			case *ast.Ident:
+				if t.Name == name {
+					lit = l
+				}
+			case *ast.SelectorExpr:
+				if t.Sel.Name == name {
+					lit = l
+				}
+			}
+		}
+		return lit == nil
+	})
+	return lit
+}
+
+// findCall returns the first call to the function name in node, e.g.: types.NewMsgCreatePost.
+func findCall(node ast.Node, name string) *ast.CallExpr {
+	var call *ast.CallExpr
+	ast.Inspect(node, func(n ast.Node) bool {
+		c, ok := n.(*ast.CallExpr)
+		if !ok {
+			return call == nil
+		}
+		var fun string
+		switch f := c.Fun.(type) {
+		case *ast.Ident:
+			fun = f.Name
+		case *ast.SelectorExpr:
+			if x, ok := f.X.(*ast.Ident); ok {
+				fun = x.Name + "." + f.Sel.Name
+			}
+		}
+		if fun == name {
+			call = c
+		}
+		return call == nil
+	})
+	return call
+}
+
+// findAssign returns the first statement assigning the variable name in node,
+// the variable can be a field, e.g.: app.marsKeeper.
+func findAssign(node ast.Node, name string) *ast.AssignStmt {
+	var stmt *ast.AssignStmt
+	ast.Inspect(node, func(n ast.Node) bool {
+		if s, ok := n.(*ast.AssignStmt); ok {
+			for _, lhs := range s.Lhs {
+				if types.ExprString(lhs) == name {
+					stmt = s
+				}
+			}
+		}
+		return stmt == nil
+	})
+	return stmt
+}
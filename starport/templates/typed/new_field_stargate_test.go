@@ -0,0 +1,59 @@
+package typed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtoAppendField(t *testing.T) {
+	field := Field{Name: "likes", Datatype: "int32"}
+	cases := []struct {
+		name    string
+		message string
+		want    string
+		err     string
+	}{
+		{
+			name:    "after the highest number",
+			message: "message Post {\n  string creator = 1;\n  string title = 3;\n  uint64 id = 2;\n}\n",
+			want:    "message Post {\n  string creator = 1;\n  string title = 3;\n  uint64 id = 2;\n  int32 likes = 4;\n}\n",
+		},
+		{
+			name:    "after the reserved numbers",
+			message: "message Post {\n  string creator = 1;\n  reserved 2, 5 to 7;\n}\n",
+			want:    "message Post {\n  string creator = 1;\n  reserved 2, 5 to 7;\n  int32 likes = 8;\n}\n",
+		},
+		{
+			name:    "nested messages",
+			message: "message Post {\n  message Meta { string tag = 9; }\n  string creator = 1;\n}\n",
+			want:    "message Post {\n  message Meta { string tag = 9; }\n  string creator = 1;\n  int32 likes = 10;\n}\n",
+		},
+		{
+			name:    "all numbers reserved",
+			message: "message Post {\n  reserved 1 to max;\n}\n",
+			err:     "the message Post reserves all the field numbers",
+		},
+		{
+			name:    "existing field",
+			message: "message Post {\n  int32 likes = 1;\n}\n",
+			err:     "the field likes already exists in the message Post",
+		},
+		{
+			name:    "missing message",
+			message: "message Comment {\n}\n",
+			err:     "cannot find the message Post",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := protoAppendField(tt.message, "Post", field)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, content)
+		})
+	}
+}
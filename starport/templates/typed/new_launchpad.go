@@ -7,6 +7,7 @@ import (
 
 	"github.com/gobuffalo/genny"
 	"github.com/tendermint/starport/starport/pkg/cosmosver"
+	"github.com/tendermint/starport/starport/pkg/xgenny"
 )
 
 type typedLaunchpad struct {
@@ -31,7 +32,7 @@ func NewLaunchpad(opts *Options) (*genny.Generator, error) {
 func (t *typedLaunchpad) handlerModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/handler.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -52,7 +53,7 @@ func (t *typedLaunchpad) handlerModify(opts *Options) genny.RunFn {
 func (t *typedLaunchpad) typesKeyModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/types/key.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -70,7 +71,7 @@ const (
 func (t *typedLaunchpad) typesCodecModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/types/codec.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -88,7 +89,7 @@ func (t *typedLaunchpad) typesCodecModify(opts *Options) genny.RunFn {
 func (t *typedLaunchpad) clientCliTxModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/client/cli/tx.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -106,7 +107,7 @@ func (t *typedLaunchpad) clientCliTxModify(opts *Options) genny.RunFn {
 func (t *typedLaunchpad) clientCliQueryModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/client/cli/query.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -123,7 +124,7 @@ func (t *typedLaunchpad) clientCliQueryModify(opts *Options) genny.RunFn {
 func (t *typedLaunchpad) typesQuerierModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/types/querier.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -140,7 +141,7 @@ func (t *typedLaunchpad) typesQuerierModify(opts *Options) genny.RunFn {
 func (t *typedLaunchpad) keeperQuerierModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/keeper/querier.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -168,7 +169,7 @@ func (t *typedLaunchpad) keeperQuerierModify(opts *Options) genny.RunFn {
 func (t *typedLaunchpad) clientRestRestModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/client/rest/rest.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -190,7 +191,7 @@ func (t *typedLaunchpad) clientRestRestModify(opts *Options) genny.RunFn {
 func (t *typedLaunchpad) frontendSrcStoreAppModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := "vue/src/views/Index.vue"
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if os.IsNotExist(err) {
 			// Skip modification if the app doesn't contain front-end
 			return nil
@@ -2,12 +2,17 @@ package typed
 
 import (
 	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
 	"os"
 	"strings"
 
 	"github.com/gertd/go-pluralize"
 	"github.com/gobuffalo/genny"
 	"github.com/tendermint/starport/starport/pkg/cosmosver"
+	"github.com/tendermint/starport/starport/pkg/xgenny"
+	"github.com/tendermint/starport/starport/templates/module"
 )
 
 type typedStargate struct {
@@ -18,6 +23,8 @@ func NewStargate(opts *Options) (*genny.Generator, error) {
 	t := typedStargate{}
 	g := genny.New()
 	g.RunFn(t.handlerModify(opts))
+	g.RunFn(t.handlerReferenceModify(opts))
+	g.RunFn(t.keeperReferenceModify(opts))
 	g.RunFn(t.typesKeyModify(opts))
 	g.RunFn(t.typesCodecModify(opts))
 	g.RunFn(t.typesCodecImportModify(opts))
@@ -32,6 +39,7 @@ func NewStargate(opts *Options) (*genny.Generator, error) {
 	g.RunFn(t.keeperQueryModify(opts))
 	g.RunFn(t.clientRestRestModify(opts))
 	g.RunFn(t.frontendSrcStoreAppModify(opts))
+	g.RunFn(t.invariantsModify(opts))
 	t.genesisModify(opts, g)
 	return g, box(cosmosver.Stargate, opts, g)
 }
@@ -39,7 +47,7 @@ func NewStargate(opts *Options) (*genny.Generator, error) {
 func (t *typedStargate) handlerModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/handler.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -60,10 +68,222 @@ case *types.MsgDelete%[2]v:
 	}
 }
 
+// handlerReferenceModify applies the deletion behavior of the references
+// to the delete handlers of the referenced types.
+func (t *typedStargate) handlerReferenceModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		for _, field := range opts.References() {
+			if field.ReferenceModule != "" {
+				continue
+			}
+			path := fmt.Sprintf("x/%s/handler_%s.go", opts.ModuleName, field.Reference)
+			f, err := xgenny.Find(r, path)
+			if err != nil {
+				return err
+			}
+			if err := CheckHandlerReference(path, f.String(), field.Reference); err != nil {
+				return err
+			}
+
+			var template string
+			switch field.OnDelete {
+			case OnDeleteCascade:
+				template = `%[1]v
+	// Deletes the %[2]v referencing the %[4]v, with the objects referencing them
+	for _, elem := range k.GetAll%[3]vBy%[5]v(ctx, id) {
+		if err := delete%[3]v(ctx, k, elem.Id); err != nil {
+			return err
+		}
+	}
+`
+			default:
+				template = `%[1]v
+	// Checks that no %[2]v references the %[4]v
+	if len(k.GetAll%[3]vBy%[5]v(ctx, id)) > 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, fmt.Sprintf("%[4]v %%s is referenced by a %[2]v", id))
+	}
+`
+			}
+			replacement := fmt.Sprintf(template,
+				placeholderHandlerDelete,
+				opts.TypeName,
+				strings.Title(opts.TypeName),
+				field.Reference,
+				strings.Title(field.Name),
+			)
+			content := strings.Replace(f.String(), placeholderHandlerDelete, replacement, 1)
+			newFile := genny.NewFileS(path, content)
+			if err := r.File(newFile); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// CheckHandlerReference checks that the handler of the type reference at path, whose source is
+// content, contains the placeholder where the deletion behavior of the references is added.
+func CheckHandlerReference(path, content, reference string) error {
+	if !strings.Contains(content, placeholderHandlerDelete) {
+		return fmt.Errorf("%s doesn't contain the scaffolding placeholder for references, add %q before the deletion in delete%s",
+			path,
+			placeholderHandlerDelete,
+			strings.Title(reference),
+		)
+	}
+	return nil
+}
+
+// keeperReferenceModify wires the keepers of the modules of the types referenced from other
+// modules to the keeper of the module, the referenced objects are checked through an expected
+// keeper interface of the module.
+func (t *typedStargate) keeperReferenceModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		for _, field := range opts.ModuleReferences() {
+			if err := expectedKeeperModify(r, opts, field); err != nil {
+				return err
+			}
+			if err := keeperFieldModify(r, opts, field); err != nil {
+				return err
+			}
+			if err := appKeeperModify(r, opts, field); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// expectedKeeperModify adds the method checking the objects referenced by field to the
+// interface of the keeper of their module.
+func expectedKeeperModify(r *genny.Runner, opts *Options, field Field) error {
+	var (
+		path    = fmt.Sprintf("x/%s/types/expected_keepers.go", opts.ModuleName)
+		keeper  = strings.Title(field.ReferenceModule) + "Keeper"
+		method  = fmt.Sprintf("Has%s(ctx sdk.Context, id string) bool", strings.Title(field.Reference))
+		comment = fmt.Sprintf("// %s defines the expected %s keeper used by the module.", keeper, field.ReferenceModule)
+	)
+	_, err := xgenny.Find(r, path)
+	if os.IsNotExist(err) {
+		content := fmt.Sprintf(`package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+%s
+type %s interface {
+	%s
+}
+`, comment, keeper, method)
+		return r.File(genny.NewFileS(path, content))
+	}
+	if err != nil {
+		return err
+	}
+	return modifyGoFile(r, path, func(fset *token.FileSet, file *ast.File, content string) ([]goEdit, error) {
+		var iface *ast.InterfaceType
+		ast.Inspect(file, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.TypeSpec); ok && spec.Name.Name == keeper {
+				iface, _ = spec.Type.(*ast.InterfaceType)
+			}
+			return iface == nil
+		})
+		if iface == nil {
+			edits := addNamedImport(fset, file, "sdk", "github.com/cosmos/cosmos-sdk/types")
+			return append(edits, goEdit{
+				start: len(content),
+				end:   len(content),
+				text:  fmt.Sprintf("\n%s\ntype %s interface {\n\t%s\n}\n", comment, keeper, method),
+			}), nil
+		}
+		for _, m := range iface.Methods.List {
+			for _, name := range m.Names {
+				if name.Name == "Has"+strings.Title(field.Reference) {
+					return nil, nil
+				}
+			}
+		}
+		return []goEdit{insertAt(fset, lineStart(fset, content, iface.Methods.Closing), "\t"+method+"\n")}, nil
+	})
+}
+
+// keeperFieldModify adds the keeper of the module of the objects referenced by field to the keeper
+// of the module, with the method checking these objects.
+func keeperFieldModify(r *genny.Runner, opts *Options, field Field) error {
+	path := fmt.Sprintf("x/%s/keeper/keeper.go", opts.ModuleName)
+	return modifyGoFile(r, path, func(fset *token.FileSet, file *ast.File, content string) ([]goEdit, error) {
+		var (
+			edits  []goEdit
+			name   = field.ReferenceModule + "Keeper"
+			keeper = strings.Title(field.ReferenceModule) + "Keeper"
+			has    = fmt.Sprintf("Has%s%s", strings.Title(field.ReferenceModule), strings.Title(field.Reference))
+		)
+		st := findStruct(file, "Keeper")
+		fn := findFunc(file, "NewKeeper")
+		if st == nil || fn == nil {
+			return nil, fmt.Errorf("cannot find the Keeper and its NewKeeper function")
+		}
+		var wired bool
+		for _, f := range st.Fields.List {
+			for _, n := range f.Names {
+				wired = wired || n.Name == name
+			}
+		}
+		if !wired {
+			params := fn.Type.Params.List
+			lit := findCompositeLit(fn, "Keeper")
+			if len(params) == 0 || lit == nil {
+				return nil, fmt.Errorf("cannot find the keeper built by NewKeeper")
+			}
+			edits = append(edits,
+				appendStructField(fset, content, st, fmt.Sprintf("%s types.%s", name, keeper)),
+				insertAt(fset, params[len(params)-1].End(), fmt.Sprintf(", %s types.%s", name, keeper)),
+				appendElt(fset, content, lit, fmt.Sprintf("%[1]s: %[1]s", name)),
+			)
+		}
+		if findFunc(file, has) == nil {
+			edits = append(edits, goEdit{
+				start: len(content),
+				end:   len(content),
+				text: fmt.Sprintf(`
+// %[1]s checks if the %[2]s with id exists in the module %[3]s.
+func (k Keeper) %[1]s(ctx sdk.Context, id string) bool {
+	return k.%[4]s.Has%[5]s(ctx, id)
+}
+`, has, field.Reference, field.ReferenceModule, name, strings.Title(field.Reference)),
+			})
+		}
+		return edits, nil
+	})
+}
+
+// appKeeperModify passes the keeper of the module of the objects referenced by field to the keeper
+// of the module in the app, the referenced keeper must be created first.
+func appKeeperModify(r *genny.Runner, opts *Options, field Field) error {
+	return modifyGoFile(r, module.PathAppGo, func(fset *token.FileSet, file *ast.File, content string) ([]goEdit, error) {
+		newKeeper := opts.ModuleName + "keeper.NewKeeper"
+		call := findCall(file, newKeeper)
+		if call == nil || len(call.Args) == 0 {
+			return nil, fmt.Errorf("cannot find the call to %s", newKeeper)
+		}
+		keeper := fmt.Sprintf("app.%sKeeper", field.ReferenceModule)
+		for _, arg := range call.Args {
+			if types.ExprString(arg) == keeper {
+				return nil, nil
+			}
+		}
+		if stmt := findAssign(file, keeper); stmt == nil || stmt.Pos() > call.Pos() {
+			return nil, fmt.Errorf("%s must be created before the keeper of the module %s", keeper, opts.ModuleName)
+		}
+		return []goEdit{appendArgs(fset, call, []string{keeper})}, nil
+	})
+}
+
 func (t *typedStargate) protoRPCImportModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("proto/%s/query.proto", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -82,7 +302,7 @@ import "%s/%s.proto";`
 func (t *typedStargate) protoRPCModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("proto/%s/query.proto", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -102,6 +322,36 @@ func (t *typedStargate) protoRPCModify(opts *Options) genny.RunFn {
 			opts.AppName,
 			opts.ModuleName,
 		)
+		for _, field := range opts.UnindexedReferences() {
+			templateReference := `	rpc %[1]vBy%[2]v(Query%[1]vBy%[2]vRequest) returns (Query%[1]vBy%[2]vResponse) {
+		option (google.api.http).get = "/%[4]v/%[5]v/%[6]v/%[3]v/%[7]v/{%[7]v}";
+	}
+`
+			replacement += fmt.Sprintf(templateReference,
+				strings.Title(opts.TypeName),
+				strings.Title(field.Name),
+				opts.TypeName,
+				opts.OwnerName,
+				opts.AppName,
+				opts.ModuleName,
+				field.Name,
+			)
+		}
+		for _, field := range opts.Indexes() {
+			templateIndex := `	rpc List%[1]vBy%[2]v(QueryList%[1]vBy%[2]vRequest) returns (QueryList%[1]vBy%[2]vResponse) {
+		option (google.api.http).get = "/%[4]v/%[5]v/%[6]v/%[3]v/by-%[7]v/{%[7]v}";
+	}
+`
+			replacement += fmt.Sprintf(templateIndex,
+				strings.Title(opts.TypeName),
+				strings.Title(field.Name),
+				opts.TypeName,
+				opts.OwnerName,
+				opts.AppName,
+				opts.ModuleName,
+				field.Name,
+			)
+		}
 		content := strings.Replace(f.String(), placeholder2, replacement, 1)
 		newFile := genny.NewFileS(path, content)
 		return r.File(newFile)
@@ -111,7 +361,7 @@ func (t *typedStargate) protoRPCModify(opts *Options) genny.RunFn {
 func (t *typedStargate) protoRPCMessageModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("proto/%s/query.proto", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -136,6 +386,43 @@ message QueryAll%[2]vResponse {
 			strings.Title(opts.TypeName),
 			opts.TypeName,
 		)
+		for _, field := range opts.UnindexedReferences() {
+			templateReference := `
+
+message Query%[1]vBy%[2]vRequest {
+	string %[3]v = 1;
+	cosmos.base.query.v1beta1.PageRequest pagination = 2;
+}
+
+message Query%[1]vBy%[2]vResponse {
+	repeated %[1]v %[1]v = 1;
+	cosmos.base.query.v1beta1.PageResponse pagination = 2;
+}`
+			replacement += fmt.Sprintf(templateReference,
+				strings.Title(opts.TypeName),
+				strings.Title(field.Name),
+				field.Name,
+			)
+		}
+		for _, field := range opts.Indexes() {
+			templateIndex := `
+
+message QueryList%[1]vBy%[2]vRequest {
+	%[4]v %[3]v = 1;
+	cosmos.base.query.v1beta1.PageRequest pagination = 2;
+}
+
+message QueryList%[1]vBy%[2]vResponse {
+	repeated %[1]v %[1]v = 1;
+	cosmos.base.query.v1beta1.PageResponse pagination = 2;
+}`
+			replacement += fmt.Sprintf(templateIndex,
+				strings.Title(opts.TypeName),
+				strings.Title(field.Name),
+				field.Name,
+				field.Datatype,
+			)
+		}
 		content := strings.Replace(f.String(), placeholder3, replacement, 1)
 		newFile := genny.NewFileS(path, content)
 		return r.File(newFile)
@@ -145,7 +432,7 @@ message QueryAll%[2]vResponse {
 func (t *typedStargate) moduleGRPCGateway(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/module.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -161,16 +448,24 @@ func (t *typedStargate) moduleGRPCGateway(opts *Options) genny.RunFn {
 func (t *typedStargate) typesKeyModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/types/keys.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
+		var indexKeys string
+		for _, field := range opts.Indexes() {
+			indexKeys += fmt.Sprintf("	%[1]vBy%[2]vKey= \"%[1]v-by-%[3]v-\"\n",
+				strings.Title(opts.TypeName),
+				strings.Title(field.Name),
+				field.Name,
+			)
+		}
 		content := f.String() + fmt.Sprintf(`
 const (
 	%[1]vKey= "%[1]v-value-"
 	%[1]vCountKey= "%[1]v-count-"
-)
-`, strings.Title(opts.TypeName))
+%[2]v)
+`, strings.Title(opts.TypeName), indexKeys)
 		newFile := genny.NewFileS(path, content)
 		return r.File(newFile)
 	}
@@ -179,7 +474,7 @@ const (
 func (t *typedStargate) typesCodecImportModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/types/codec.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -193,7 +488,7 @@ func (t *typedStargate) typesCodecImportModify(opts *Options) genny.RunFn {
 func (t *typedStargate) typesCodecModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/types/codec.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -212,7 +507,7 @@ cdc.RegisterConcrete(&MsgDelete%[2]v{}, "%[3]v/Delete%[2]v", nil)
 func (t *typedStargate) typesCodecInterfaceModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/types/codec.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -232,7 +527,7 @@ registry.RegisterImplementations((*sdk.Msg)(nil),
 func (t *typedStargate) clientCliTxModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/client/cli/tx.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -252,7 +547,7 @@ func (t *typedStargate) clientCliTxModify(opts *Options) genny.RunFn {
 func (t *typedStargate) clientCliQueryModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/client/cli/query.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -264,6 +559,17 @@ func (t *typedStargate) clientCliQueryModify(opts *Options) genny.RunFn {
 		replacement := fmt.Sprintf(template, placeholder,
 			strings.Title(opts.TypeName),
 		)
+		// indexed references are listed with the command of their index,
+		// both commands share the same name
+		for _, field := range opts.Fields {
+			if field.Reference == "" && !field.Indexed {
+				continue
+			}
+			replacement += fmt.Sprintf("	cmd.AddCommand(CmdList%vBy%v())\n",
+				strings.Title(opts.TypeName),
+				strings.Title(field.Name),
+			)
+		}
 		content := strings.Replace(f.String(), placeholder, replacement, 1)
 		newFile := genny.NewFileS(path, content)
 		return r.File(newFile)
@@ -273,7 +579,7 @@ func (t *typedStargate) clientCliQueryModify(opts *Options) genny.RunFn {
 func (t *typedStargate) typesQueryModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/types/query.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -292,7 +598,7 @@ const (
 func (t *typedStargate) keeperQueryModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/keeper/query.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -322,7 +628,7 @@ func (t *typedStargate) keeperQueryModify(opts *Options) genny.RunFn {
 func (t *typedStargate) clientRestRestModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := fmt.Sprintf("x/%s/client/rest/rest.go", opts.ModuleName)
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if err != nil {
 			return err
 		}
@@ -357,7 +663,7 @@ func (t *typedStargate) clientRestRestModify(opts *Options) genny.RunFn {
 func (t *typedStargate) frontendSrcStoreAppModify(opts *Options) genny.RunFn {
 	return func(r *genny.Runner) error {
 		path := "vue/src/views/Index.vue"
-		f, err := r.Disk.Find(path)
+		f, err := xgenny.Find(r, path)
 		if os.IsNotExist(err) {
 			// Skip modification if the app doesn't contain front-end
 			return nil
@@ -367,6 +673,10 @@ func (t *typedStargate) frontendSrcStoreAppModify(opts *Options) genny.RunFn {
 		}
 		fields := []string{` ['creator', 1, 'string'] `}
 		for id, field := range opts.Fields {
+			// the form only handles scalar fields, the lists are entered from the page of the type
+			if field.IsRepeated() {
+				continue
+			}
 			fields = append(fields, fmt.Sprintf(` ['%s', %d, '%s'] `, field.Name, id+2, field.Datatype))
 		}
 		replacement := fmt.Sprintf(`%[1]v
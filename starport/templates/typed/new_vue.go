@@ -0,0 +1,69 @@
+package typed
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gobuffalo/genny"
+	"github.com/gobuffalo/packr/v2"
+	"github.com/tendermint/starport/starport/pkg/xgenny"
+)
+
+// PathVueRouter is the path of the router of the frontend.
+const PathVueRouter = "vue/src/router/index.js"
+
+// these needs to be created in the compiler time, otherwise packr2 won't be
+// able to find boxes.
+var frontendTemplate = packr.New("typed/templates/frontend", "./frontend")
+
+// NewVue returns the generator creating the view and the components of a type in the
+// Vue frontend of a Stargate app and registering the view in the router.
+func NewVue(opts *Options) (*genny.Generator, error) {
+	g := genny.New()
+	g.RunFn(frontendRouterModify(opts))
+	if err := g.Box(frontendTemplate); err != nil {
+		return g, err
+	}
+	return g, transformers(opts, g)
+}
+
+// frontendRouterModify registers the route of the view of the type and adds it to the navigation.
+// the routers created before the placeholders get the route at the end of the list of routes.
+func frontendRouterModify(opts *Options) genny.RunFn {
+	return func(r *genny.Runner) error {
+		f, err := xgenny.Find(r, PathVueRouter)
+		if err != nil {
+			return err
+		}
+		content := f.String()
+
+		var (
+			title     = strings.Title(opts.TypeName)
+			component = fmt.Sprintf("%s%sView", strings.Title(opts.ModuleName), title)
+			path      = fmt.Sprintf("/%s/%s", opts.ModuleName, opts.TypeName)
+		)
+
+		importLine := fmt.Sprintf(`import %[1]v from "../views/%[2]v/%[3]v.vue";`, component, opts.ModuleName, title)
+		route := fmt.Sprintf(`{ path: "%[1]v", component: %[2]v },`, path, component)
+		if strings.Contains(content, placeholderRouterRoute) {
+			content = strings.Replace(content, placeholderRouterImport, importLine+"\n"+placeholderRouterImport, 1)
+			content = strings.Replace(content, placeholderRouterRoute, route+"\n  "+placeholderRouterRoute, 1)
+		} else {
+			const (
+				vueRouterImport = `import VueRouter from "vue-router";`
+				routesDecl      = "const routes = ["
+			)
+			if !strings.Contains(content, routesDecl) {
+				return fmt.Errorf("%s doesn't contain the routes of the frontend", PathVueRouter)
+			}
+			content = strings.Replace(content, vueRouterImport, vueRouterImport+"\n"+importLine, 1)
+			content = strings.Replace(content, routesDecl, routesDecl+"\n  "+route, 1)
+		}
+
+		navigation := fmt.Sprintf(`{ path: "%[1]v", title: "%[2]v" },`, path, title)
+		content = strings.Replace(content, placeholderRouterNavigation, navigation+"\n  "+placeholderRouterNavigation, 1)
+
+		newFile := genny.NewFileS(PathVueRouter, content)
+		return r.File(newFile)
+	}
+}
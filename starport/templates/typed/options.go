@@ -1,10 +1,51 @@
 package typed
 
+import "strings"
+
+const (
+	// OnDeleteRestrict prevents deleting an object while other objects reference it.
+	OnDeleteRestrict = "restrict"
+
+	// OnDeleteCascade deletes the objects referencing an object when it is deleted.
+	OnDeleteCascade = "cascade"
+)
+
 // Field ...
 type Field struct {
 	Name         string
 	Datatype     string
 	DatatypeName string
+
+	// Reference is the name of the type the field references by id, if any.
+	Reference string
+
+	// ReferenceModule is the module of the referenced type when it's not the module of the field.
+	ReferenceModule string
+
+	// OnDelete is OnDeleteRestrict or OnDeleteCascade for references to a type of the same module.
+	// the deletion of the types of other modules isn't restricted by their references.
+	OnDelete string
+
+	// Indexed is true when a secondary index is maintained for the field.
+	Indexed bool
+}
+
+// IsRepeated checks if the field holds a list of values, e.g.: []string.
+func (f Field) IsRepeated() bool {
+	return strings.HasPrefix(f.Datatype, "[]")
+}
+
+// ScalarType returns the type of the values held by the field.
+func (f Field) ScalarType() string {
+	return strings.TrimPrefix(f.Datatype, "[]")
+}
+
+// ProtoType returns the type of the field in the proto files.
+func (f Field) ProtoType() string {
+	if f.IsRepeated() {
+		return "repeated " + f.ScalarType()
+	}
+	return f.Datatype
 }
 
 // Options ...
@@ -17,6 +58,57 @@ type Options struct {
 	Fields     []Field
 }
 
+// References returns the fields referencing another type.
+func (opts *Options) References() []Field {
+	var fields []Field
+	for _, field := range opts.Fields {
+		if field.Reference != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// ModuleReferences returns the fields referencing a type of another module, once per referenced type.
+func (opts *Options) ModuleReferences() []Field {
+	var (
+		fields     []Field
+		referenced = make(map[string]bool)
+	)
+	for _, field := range opts.References() {
+		key := field.ReferenceModule + "." + field.Reference
+		if field.ReferenceModule == "" || referenced[key] {
+			continue
+		}
+		referenced[key] = true
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// UnindexedReferences returns the fields referencing another type without an index.
+// indexed references are listed through the queries of their index.
+func (opts *Options) UnindexedReferences() []Field {
+	var fields []Field
+	for _, field := range opts.References() {
+		if !field.Indexed {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Indexes returns the fields having a secondary index.
+func (opts *Options) Indexes() []Field {
+	var fields []Field
+	for _, field := range opts.Fields {
+		if field.Indexed {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
 // Validate that options are usuable
 func (opts *Options) Validate() error {
 	return nil
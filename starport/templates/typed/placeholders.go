@@ -7,6 +7,9 @@ const (
 	placeholder4  = "<!-- this line is used by starport scaffolding # 4 -->"
 	placeholder44 = "// this line is used by starport scaffolding # 4"
 
+	// Handler
+	placeholderHandlerDelete = "// this line is used by starport scaffolding # handler/delete"
+
 	// Genesis
 	placeholderGenesisProtoImport     = "// this line is used by starport scaffolding # genesis/proto/import"
 	placeholderGenesisProtoState      = "// this line is used by starport scaffolding # genesis/proto/state"
@@ -16,4 +19,12 @@ const (
 	placeholderGenesisTypesValidate   = "// this line is used by starport scaffolding # genesis/types/validate"
 	placeholderGenesisModuleInit      = "// this line is used by starport scaffolding # genesis/module/init"
 	placeholderGenesisModuleExport    = "// this line is used by starport scaffolding # genesis/module/export"
+
+	// Invariants
+	placeholderInvariantsRegister = "// this line is used by starport scaffolding # invariants/register"
+
+	// Router of the frontend
+	placeholderRouterImport     = "// this line is used by starport scaffolding # router/import"
+	placeholderRouterRoute      = "// this line is used by starport scaffolding # router/route"
+	placeholderRouterNavigation = "// this line is used by starport scaffolding # router/navigation"
 )
@@ -21,6 +21,11 @@ func box(sdkVersion cosmosver.MajorVersion, opts *Options, g *genny.Generator) e
 	if err := g.Box(templates[sdkVersion]); err != nil {
 		return err
 	}
+	return transformers(opts, g)
+}
+
+// transformers adds the transformers rendering the templates of a type to g.
+func transformers(opts *Options, g *genny.Generator) error {
 	ctx := plush.NewContext()
 	ctx.Set("ModuleName", opts.ModuleName)
 	ctx.Set("AppName", opts.AppName)
@@ -28,16 +33,35 @@ func box(sdkVersion cosmosver.MajorVersion, opts *Options, g *genny.Generator) e
 	ctx.Set("OwnerName", opts.OwnerName)
 	ctx.Set("ModulePath", opts.ModulePath)
 	ctx.Set("Fields", opts.Fields)
+	ctx.Set("References", opts.References())
+	ctx.Set("UnindexedReferences", opts.UnindexedReferences())
+	ctx.Set("Indexes", opts.Indexes())
 	ctx.Set("title", strings.Title)
 	ctx.Set("strconv", func() bool {
 		strconv := false
 		for _, field := range opts.Fields {
-			if field.DatatypeName != "string" {
+			if field.DatatypeName != "string" && !field.IsRepeated() {
 				strconv = true
 			}
 		}
 		return strconv
 	})
+	ctx.Set("strings", func() bool {
+		for _, field := range opts.Fields {
+			if field.IsRepeated() {
+				return true
+			}
+		}
+		return false
+	})
+	ctx.Set("indexStrconv", func() bool {
+		for _, field := range opts.Indexes() {
+			if field.DatatypeName != "string" {
+				return true
+			}
+		}
+		return false
+	})
 	ctx.Set("nodash", func(s string) string {
 		return strings.ReplaceAll(s, "-", "")
 	})
@@ -47,3 +71,10 @@ func box(sdkVersion cosmosver.MajorVersion, opts *Options, g *genny.Generator) e
 	g.Transformer(genny.Replace("{{TypeName}}", strings.Title(opts.TypeName)))
 	return nil
 }
+
+// NewFiles returns the generator creating the files of a type without modifying
+// the other files of the app. it's used to render the files of a type again.
+func NewFiles(sdkVersion cosmosver.MajorVersion, opts *Options) (*genny.Generator, error) {
+	g := genny.New()
+	return g, box(sdkVersion, opts, g)
+}